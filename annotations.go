@@ -0,0 +1,214 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/signintech/gopdf"
+)
+
+// Annotations are vector marks drawn over the sheet or cutout in the PDF.
+// Points are normalized to the image after its edits, so they follow it
+// wherever fitWithinRegion or fitWithinPage places it.
+
+type AnnotationKind string
+
+const (
+	ANNOTATION_ARROW       AnnotationKind = "arrow"       // from Points[0] to Points[1]
+	ANNOTATION_RECT        AnnotationKind = "rect"        // between opposite corners Points[0] and Points[1]
+	ANNOTATION_ELLIPSE     AnnotationKind = "ellipse"     // inscribed in the rect of Points[0] and Points[1]
+	ANNOTATION_TEXT        AnnotationKind = "text"        // Text with its top left corner at Points[0]
+	ANNOTATION_HIGHLIGHTER AnnotationKind = "highlighter" // a stroke through Points
+)
+
+type Annotation struct {
+	Kind     AnnotationKind `json:"kind"`
+	Points   []Point        `json:"points"`
+	Text     string         `json:"text,omitempty"`
+	Color    string         `json:"color"`   // #RRGGBB
+	Width    float64        `json:"width"`   // stroke width, in points
+	Opacity  float64        `json:"opacity"` // 0-1, 0 picks the default of the kind
+	FontSize float64        `json:"fontSize,omitempty"`
+}
+
+const (
+	maxAnnotationWidth = 50.0
+	defaultFontSize    = 12.0
+)
+
+func (an Annotation) validate() error {
+	inside := func(p Point) bool { return p.X >= 0 && p.X <= 1 && p.Y >= 0 && p.Y <= 1 }
+	want := map[AnnotationKind]int{
+		ANNOTATION_ARROW:       2,
+		ANNOTATION_RECT:        2,
+		ANNOTATION_ELLIPSE:     2,
+		ANNOTATION_TEXT:        1,
+		ANNOTATION_HIGHLIGHTER: 2, // at least
+	}
+	n, ok := want[an.Kind]
+	switch {
+	case !ok:
+		return fmt.Errorf("unknown annotation %q", an.Kind)
+	case an.Kind == ANNOTATION_HIGHLIGHTER && len(an.Points) < n:
+		return errors.New("a highlighter stroke needs at least two points")
+	case an.Kind != ANNOTATION_HIGHLIGHTER && len(an.Points) != n:
+		return fmt.Errorf("%s needs %d points", an.Kind, n)
+	case slices.ContainsFunc(an.Points, func(p Point) bool { return !inside(p) }):
+		return errors.New("annotation points must be inside the image")
+	case an.Kind == ANNOTATION_TEXT && strings.TrimSpace(an.Text) == "":
+		return errors.New("text annotations need text")
+	case an.Kind != ANNOTATION_TEXT && (an.Width <= 0 || an.Width > maxAnnotationWidth):
+		return fmt.Errorf("width must be between 0 and %v points", maxAnnotationWidth)
+	case an.Opacity < 0 || an.Opacity > 1:
+		return errors.New("opacity must be between 0 and 1")
+	case an.FontSize < 0 || an.FontSize > 200:
+		return errors.New("font size must be between 0 and 200 points")
+	}
+	_, _, _, err := parseColor(an.Color)
+	return err
+}
+
+// opacity returns the opacity to draw an with. Highlighter strokes are
+// translucent unless told otherwise.
+func (an Annotation) opacity() float64 {
+	switch {
+	case an.Opacity > 0:
+		return an.Opacity
+	case an.Kind == ANNOTATION_HIGHLIGHTER:
+		return 0.4
+	default:
+		return 1
+	}
+}
+
+// SetAnnotations replaces the annotations of the sheet or cutout of an asset.
+func (a *App) SetAnnotations(projectId string, assetId string, photo PhotoType, annotations []Annotation) error {
+	if projectId == "" || assetId == "" {
+		return fmt.Errorf("required project or asset ID not found")
+	}
+	for i, an := range annotations {
+		if err := an.validate(); err != nil {
+			return fmt.Errorf("annotation %d: %w", i+1, err)
+		}
+	}
+
+	base, err := a.workspaceDir()
+	if err != nil {
+		return err
+	}
+	proj, err := readProject(base, projectId)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(proj.Assets, func(as AssetMetadata) bool { return as.ID == assetId })
+	if i == -1 {
+		return fmt.Errorf("asset with ID %s not found in project", assetId)
+	}
+	as := &proj.Assets[i]
+	switch photo {
+	case SHEET:
+		as.SheetAnnotations = annotations
+	case CUTOUT:
+		as.CutoutAnnotations = annotations
+	default:
+		return fmt.Errorf("unknown photo type %q", photo)
+	}
+	as.UpdatedAt = timestamp()
+	return writeProject(base, proj)
+}
+
+// drawAnnotations draws annotations over an image placed at x, y with size
+// rect.
+func drawAnnotations(pdf *gopdf.GoPdf, annotations []Annotation, x, y float64, rect *gopdf.Rect) error {
+	at := func(p Point) gopdf.Point { return gopdf.Point{X: x + p.X*rect.W, Y: y + p.Y*rect.H} }
+	for _, an := range annotations {
+		r, g, b, err := parseColor(an.Color)
+		if err != nil {
+			return err
+		}
+		pdf.SetStrokeColor(r, g, b)
+		pdf.SetFillColor(r, g, b)
+		pdf.SetTextColor(r, g, b)
+		pdf.SetLineWidth(an.Width)
+		if op := an.opacity(); op < 1 {
+			blend := gopdf.NormalBlendMode
+			if an.Kind == ANNOTATION_HIGHLIGHTER {
+				// like a marker, the print underneath stays readable
+				blend = gopdf.Multiply
+			}
+			if err := pdf.SetTransparency(gopdf.Transparency{Alpha: op, BlendModeType: blend}); err != nil {
+				return err
+			}
+		}
+
+		switch an.Kind {
+		case ANNOTATION_ARROW:
+			from, to := at(an.Points[0]), at(an.Points[1])
+			drawArrow(pdf, from, to, an.Width)
+		case ANNOTATION_RECT:
+			p, q := at(an.Points[0]), at(an.Points[1])
+			pdf.RectFromUpperLeftWithStyle(min(p.X, q.X), min(p.Y, q.Y), math.Abs(q.X-p.X), math.Abs(q.Y-p.Y), "D")
+		case ANNOTATION_ELLIPSE:
+			p, q := at(an.Points[0]), at(an.Points[1])
+			pdf.Polygon(ellipse((p.X+q.X)/2, (p.Y+q.Y)/2, math.Abs(q.X-p.X)/2, math.Abs(q.Y-p.Y)/2), "D")
+		case ANNOTATION_TEXT:
+			size := an.FontSize
+			if size == 0 {
+				size = defaultFontSize
+			}
+			if err := pdf.SetFont("times", "", size); err != nil {
+				return err
+			}
+			p := at(an.Points[0])
+			pdf.SetXY(p.X, p.Y)
+			if err := pdf.Cell(nil, an.Text); err != nil {
+				return err
+			}
+		case ANNOTATION_HIGHLIGHTER:
+			for i := 1; i < len(an.Points); i++ {
+				p, q := at(an.Points[i-1]), at(an.Points[i])
+				pdf.Line(p.X, p.Y, q.X, q.Y)
+			}
+		}
+		pdf.ClearTransparency()
+	}
+	pdf.SetStrokeColor(0, 0, 0)
+	pdf.SetFillColor(0, 0, 0)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetLineWidth(1)
+	return nil
+}
+
+// drawArrow draws a line ending in a filled head sized after the width.
+func drawArrow(pdf *gopdf.GoPdf, from, to gopdf.Point, width float64) {
+	length := math.Hypot(to.X-from.X, to.Y-from.Y)
+	if length == 0 {
+		return
+	}
+	head := min(max(6, 3*width), length/2)
+	ux, uy := (to.X-from.X)/length, (to.Y-from.Y)/length
+	// the shaft stops at the base of the head so it doesn't poke through
+	base := gopdf.Point{X: to.X - ux*head, Y: to.Y - uy*head}
+	pdf.Line(from.X, from.Y, base.X, base.Y)
+	half := head / 2
+	pdf.Polygon([]gopdf.Point{
+		to,
+		{X: base.X - uy*half, Y: base.Y + ux*half},
+		{X: base.X + uy*half, Y: base.Y - ux*half},
+	}, "F")
+}
+
+// ellipse approximates an ellipse with a polygon, which unlike Oval
+// honors the current transparency.
+func ellipse(cx, cy, rx, ry float64) []gopdf.Point {
+	const steps = 72
+	pts := make([]gopdf.Point, steps)
+	for i := range pts {
+		sin, cos := math.Sincos(2 * math.Pi * float64(i) / steps)
+		pts[i] = gopdf.Point{X: cx + rx*cos, Y: cy + ry*sin}
+	}
+	return pts
+}
@@ -2,11 +2,18 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"sync"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 // App struct
 type App struct {
 	ctx context.Context
+
+	mu          sync.Mutex
+	stopBackups context.CancelFunc
 }
 
 // NewApp creates a new App application struct
@@ -18,4 +25,23 @@ func NewApp() *App {
 // so we can call the runtime methods
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
+	base, err := a.workspaceDir()
+	if err != nil {
+		fmt.Println(concat("could not resolve workspace: ", err.Error()))
+		return
+	}
+	a.openWorkspace(base)
+}
+
+// beforeClose remembers the window size for the next launch.
+func (a *App) beforeClose(ctx context.Context) (prevent bool) {
+	w, h := runtime.WindowGetSize(ctx)
+	_, err := updateSettings(func(s *Settings) error {
+		s.WindowWidth, s.WindowHeight = max(w, 800), max(h, 600)
+		return nil
+	})
+	if err != nil {
+		fmt.Println(concat("could not save window size: ", err.Error()))
+	}
+	return false
 }
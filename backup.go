@@ -0,0 +1,396 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Backups are zip snapshots of the whole workspace named
+// hyuga-backup-<timestamp>.zip, with a manifest of checksums that restore
+// verifies before touching the workspace.
+
+const (
+	backupPrefix        = "hyuga-backup-"
+	backupTimeLayout    = "20060102-150405"
+	defaultBackupKeep   = 10
+	backupManifestEntry = "backup-manifest.json"
+)
+
+// BackupSettings are part of Settings.
+type BackupSettings struct {
+	Dir string `json:"dir"`
+	// IntervalHours schedules a backup every so many hours while the app
+	// runs. Zero only backs up on demand.
+	IntervalHours int `json:"intervalHours"`
+	Keep          int `json:"keep"`
+}
+
+type BackupInfo struct {
+	Name      string `json:"name"`
+	Path      string `json:"path"`
+	CreatedAt string `json:"createdAt"`
+	Size      int64  `json:"size"`
+}
+
+type backupManifest struct {
+	CreatedAt string       `json:"createdAt"`
+	Files     []BundleFile `json:"files"`
+}
+
+// backupDir is where the snapshots of workspace w go. Each workspace gets
+// its own folder so pruning never mixes them up.
+func backupDir(s BackupSettings, w *Workspace) string {
+	return filepath.Join(s.Dir, w.Id)
+}
+
+// ChooseBackupDir asks for the folder backups are written to.
+func (a *App) ChooseBackupDir() (string, error) {
+	return runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
+		Title:                "Carpeta de respaldos",
+		CanCreateDirectories: true,
+	})
+}
+
+// scheduleBackups replaces the running backup schedule, if any.
+func (a *App) scheduleBackups(s BackupSettings) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.stopBackups != nil {
+		a.stopBackups()
+		a.stopBackups = nil
+	}
+	if s.IntervalHours <= 0 || s.Dir == "" || a.ctx == nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.stopBackups = cancel
+	go func() {
+		t := time.NewTicker(time.Duration(s.IntervalHours) * time.Hour)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				if _, err := a.CreateBackup(); err != nil {
+					fmt.Println(concat("scheduled backup failed: ", err.Error()))
+				}
+			}
+		}
+	}()
+}
+
+// CreateBackup snapshots the workspace into the backup folder and drops
+// the oldest snapshots beyond the configured count.
+func (a *App) CreateBackup() (*BackupInfo, error) {
+	w, err := a.activeWorkspace()
+	if err != nil {
+		return nil, err
+	}
+	settings, err := currentSettings()
+	if err != nil {
+		return nil, err
+	}
+	s := settings.Backup
+	if s.Dir == "" {
+		return nil, errors.New("no backup folder configured")
+	}
+	dir := backupDir(s, w)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	name := concat(backupPrefix, now.Format(backupTimeLayout), ".zip")
+	dst := filepath.Join(dir, name)
+	if err := writeSnapshot(w.Path, s.Dir, dst+".tmp", now); err != nil {
+		os.Remove(dst + ".tmp")
+		return nil, fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := os.Rename(dst+".tmp", dst); err != nil {
+		return nil, err
+	}
+
+	backups, err := listBackups(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range backups[min(s.Keep, len(backups)):] {
+		if err := os.Remove(b.Path); err != nil {
+			fmt.Println(concat("could not remove old backup ", b.Path, ": ", err.Error()))
+		}
+	}
+
+	st, err := os.Stat(dst)
+	if err != nil {
+		return nil, err
+	}
+	return &BackupInfo{Name: name, Path: dst, CreatedAt: now.Format(time.RFC3339), Size: st.Size()}, nil
+}
+
+func writeSnapshot(base, backupDir, dst string, now time.Time) error {
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	zw := zip.NewWriter(out)
+	m := backupManifest{CreatedAt: now.Format(time.RFC3339), Files: []BundleFile{}}
+
+	err = filepath.WalkDir(base, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		// never back up the backups when they live inside the workspace
+		if d.IsDir() && p == filepath.Clean(backupDir) {
+			return filepath.SkipDir
+		}
+		// caches are rebuilt on demand
+		if d.IsDir() && p == filepath.Join(base, "cache") {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || strings.HasSuffix(p, ".tmp") {
+			return nil
+		}
+		rel, err := filepath.Rel(base, p)
+		if err != nil || isAppFile(rel) {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		in, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		f, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		h := sha256.New()
+		n, err := io.Copy(io.MultiWriter(f, h), in)
+		if err != nil {
+			return err
+		}
+		m.Files = append(m.Files, BundleFile{Path: name, Sha256: hex.EncodeToString(h.Sum(nil)), Size: n})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	mb, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	f, err := zw.Create(backupManifestEntry)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(mb); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+func listBackups(dir string) ([]BackupInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []BackupInfo{}, nil
+		}
+		return nil, err
+	}
+	res := []BackupInfo{}
+	for _, e := range entries {
+		stamp, ok := strings.CutPrefix(e.Name(), backupPrefix)
+		if !ok || e.IsDir() || filepath.Ext(stamp) != ".zip" {
+			continue
+		}
+		t, err := time.ParseInLocation(backupTimeLayout, strings.TrimSuffix(stamp, ".zip"), time.Local)
+		if err != nil {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		res = append(res, BackupInfo{
+			Name:      e.Name(),
+			Path:      filepath.Join(dir, e.Name()),
+			CreatedAt: t.Format(time.RFC3339),
+			Size:      info.Size(),
+		})
+	}
+	// newest first, the layout sorts chronologically
+	slices.SortFunc(res, func(a, b BackupInfo) int { return strings.Compare(b.Name, a.Name) })
+	return res, nil
+}
+
+// ListBackups lists the snapshots of the active workspace, newest first.
+func (a *App) ListBackups() ([]BackupInfo, error) {
+	w, err := a.activeWorkspace()
+	if err != nil {
+		return nil, err
+	}
+	s, err := currentSettings()
+	if err != nil {
+		return nil, err
+	}
+	if s.Backup.Dir == "" {
+		return []BackupInfo{}, nil
+	}
+	return listBackups(backupDir(s.Backup, w))
+}
+
+// extractSnapshot verifies every file of the snapshot against the manifest
+// and writes them under dst.
+func extractSnapshot(file, dst string) error {
+	zr, err := zip.OpenReader(file)
+	if err != nil {
+		return fmt.Errorf("invalid backup: %w", err)
+	}
+	defer zr.Close()
+
+	entries := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		entries[f.Name] = f
+	}
+	mf, ok := entries[backupManifestEntry]
+	if !ok {
+		return errors.New("backup has no manifest")
+	}
+	rc, err := mf.Open()
+	if err != nil {
+		return err
+	}
+	var m backupManifest
+	err = json.NewDecoder(rc).Decode(&m)
+	rc.Close()
+	if err != nil {
+		return fmt.Errorf("invalid backup manifest: %w", err)
+	}
+
+	for _, bf := range m.Files {
+		if !fs.ValidPath(bf.Path) {
+			return fmt.Errorf("invalid path in backup: %s", bf.Path)
+		}
+		f, ok := entries[bf.Path]
+		if !ok {
+			return fmt.Errorf("backup is missing %s", bf.Path)
+		}
+		target := filepath.Join(dst, filepath.FromSlash(bf.Path))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := extractVerified(f, target, bf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractVerified(f *zip.File, target string, bf BundleFile) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(out, h), rc)
+	if err != nil {
+		return err
+	}
+	if hex.EncodeToString(h.Sum(nil)) != bf.Sha256 || n != bf.Size {
+		return fmt.Errorf("checksum mismatch for %s", bf.Path)
+	}
+	return out.Close()
+}
+
+// RestoreBackup replaces the workspace with a verified snapshot. The
+// current workspace is kept next to it as <workspace>.previous-<timestamp>
+// and is put back if the swap fails. Backups kept inside the workspace
+// stay in place. It returns the fallback location.
+func (a *App) RestoreBackup(file string) (string, error) {
+	if file == "" {
+		return "", errors.New("no backup provided")
+	}
+	base, err := a.workspaceDir()
+	if err != nil {
+		return "", err
+	}
+	w, err := a.activeWorkspace()
+	if err != nil {
+		return "", err
+	}
+	settings, err := currentSettings()
+	if err != nil {
+		return "", err
+	}
+	staging := base + ".restore"
+	if err := os.RemoveAll(staging); err != nil {
+		return "", err
+	}
+	if err := extractSnapshot(file, staging); err != nil {
+		os.RemoveAll(staging)
+		return "", err
+	}
+
+	// the default workspace shares its folder with the app files
+	for _, name := range appFiles {
+		if err := copyFile(filepath.Join(base, name), filepath.Join(staging, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			os.RemoveAll(staging)
+			return "", err
+		}
+	}
+
+	fallback := concat(base, ".previous-", time.Now().Format(backupTimeLayout))
+	if err := os.Rename(base, fallback); err != nil {
+		os.RemoveAll(staging)
+		return "", fmt.Errorf("failed to set current workspace aside: %w", err)
+	}
+	if err := os.Rename(staging, base); err != nil {
+		if rerr := os.Rename(fallback, base); rerr != nil {
+			return "", errors.Join(err, rerr)
+		}
+		return "", fmt.Errorf("failed to restore backup: %w", err)
+	}
+
+	// snapshots leave out the backups kept inside the workspace
+	if settings.Backup.Dir != "" {
+		rel, err := filepath.Rel(base, backupDir(settings.Backup, w))
+		if err == nil && !strings.HasPrefix(rel, "..") {
+			err := os.MkdirAll(filepath.Dir(filepath.Join(base, rel)), 0755)
+			if err == nil {
+				err = os.Rename(filepath.Join(fallback, rel), filepath.Join(base, rel))
+			}
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				fmt.Println(concat("could not carry backups over from ", fallback, ": ", err.Error()))
+			}
+		}
+	}
+	// the snapshot may come from another location of the workspace
+	a.openWorkspace(base)
+	return fallback, nil
+}
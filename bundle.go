@@ -0,0 +1,372 @@
+package main
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// A .hyuga bundle is a zip holding manifest.json, the project folder under
+// project/ and every machote the project references under machotes/.
+// Inside the bundle, machote references in project.json point at their
+// machotes/ entry instead of a path on the exporting machine.
+
+const bundleFormat = 1
+
+type BundleFile struct {
+	Path   string `json:"path"`
+	Sha256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+type BundleMachote struct {
+	Path  string `json:"path"`
+	Label string `json:"label"`
+}
+
+type BundleManifest struct {
+	Format      int             `json:"format"`
+	ProjectId   string          `json:"projectId"`
+	ProjectName string          `json:"projectName"`
+	ExportedAt  string          `json:"exportedAt"`
+	Files       []BundleFile    `json:"files"`
+	Machotes    []BundleMachote `json:"machotes"`
+}
+
+type bundleWriter struct {
+	zw       *zip.Writer
+	manifest BundleManifest
+}
+
+func (w *bundleWriter) add(name string, b []byte) error {
+	f, err := w.zw.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		return err
+	}
+	sum := sha256.Sum256(b)
+	w.manifest.Files = append(w.manifest.Files, BundleFile{Path: name, Sha256: hex.EncodeToString(sum[:]), Size: int64(len(b))})
+	return nil
+}
+
+// ExportProjectBundle writes the project as a .hyuga bundle to file, asking
+// for a location when file is empty. It returns the written path.
+func (a *App) ExportProjectBundle(projectId string, file string) (string, error) {
+	if projectId == "" {
+		return "", errors.New("invalid project ID")
+	}
+	base, err := a.workspaceDir()
+	if err != nil {
+		return "", err
+	}
+	proj, err := readProject(base, projectId)
+	if err != nil {
+		return "", err
+	}
+	if file == "" {
+		file, err = runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+			DefaultFilename: concat(proj.Name, ".hyuga"),
+			Title:           "Exportar proyecto",
+			Filters:         []runtime.FileFilter{{DisplayName: "Hyuga (*.hyuga)", Pattern: "*.hyuga"}},
+		})
+		if err != nil {
+			return "", err
+		}
+		if file == "" {
+			return "", errors.New("no file path provided")
+		}
+	}
+
+	lib, err := loadModelLibrary(base)
+	if err != nil {
+		return "", err
+	}
+	labels := make(map[string]string, len(lib.models))
+	for _, m := range lib.models {
+		labels[m.Value] = m.Label
+	}
+
+	out, err := os.Create(file)
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+	w := &bundleWriter{
+		zw: zip.NewWriter(out),
+		manifest: BundleManifest{
+			Format:      bundleFormat,
+			ProjectId:   proj.Id,
+			ProjectName: proj.Name,
+			ExportedAt:  timestamp(),
+			Files:       []BundleFile{},
+			Machotes:    []BundleMachote{},
+		},
+	}
+
+	// machotes are bundled once per file and referenced by their entry name
+	bundled := map[string]string{}
+	bundleMachote := func(ref string) (string, error) {
+		if !filepath.IsAbs(ref) {
+			return ref, nil
+		}
+		if name, ok := bundled[ref]; ok {
+			return name, nil
+		}
+		b, err := os.ReadFile(ref)
+		if err != nil {
+			return "", fmt.Errorf("cannot read machote %s: %w", ref, err)
+		}
+		name := path.Join("machotes", filepath.Base(ref))
+		if err := w.add(name, b); err != nil {
+			return "", err
+		}
+		label := labels[ref]
+		if label == "" {
+			label = filepath.Base(ref)
+		}
+		w.manifest.Machotes = append(w.manifest.Machotes, BundleMachote{Path: name, Label: label})
+		bundled[ref] = name
+		return name, nil
+	}
+	if proj.DefaultModel, err = bundleMachote(proj.DefaultModel); err != nil {
+		return "", err
+	}
+	for i := range proj.Assets {
+		if proj.Assets[i].Model, err = bundleMachote(proj.Assets[i].Model); err != nil {
+			return "", err
+		}
+	}
+
+	pb, err := json.MarshalIndent(proj, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := w.add("project/project.json", pb); err != nil {
+		return "", err
+	}
+	dir := projectDir(base, projectId)
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil || rel == "project.json" {
+			return err
+		}
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		return w.add(path.Join("project", filepath.ToSlash(rel)), b)
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to bundle project files: %w", err)
+	}
+
+	mb, err := json.MarshalIndent(w.manifest, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	f, err := w.zw.Create("manifest.json")
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.Write(mb); err != nil {
+		return "", err
+	}
+	if err := w.zw.Close(); err != nil {
+		return "", err
+	}
+	return file, out.Close()
+}
+
+// readBundle opens a bundle and verifies every manifest entry against its
+// checksum, returning the verified contents by entry name.
+func readBundle(file string) (*BundleManifest, map[string][]byte, error) {
+	zr, err := zip.OpenReader(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+	defer zr.Close()
+
+	entries := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		entries[f.Name] = f
+	}
+	readEntry := func(name string) ([]byte, error) {
+		f, ok := entries[name]
+		if !ok {
+			return nil, fmt.Errorf("bundle is missing %s", name)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+
+	mb, err := readEntry("manifest.json")
+	if err != nil {
+		return nil, nil, err
+	}
+	var m BundleManifest
+	if err := json.Unmarshal(mb, &m); err != nil {
+		return nil, nil, fmt.Errorf("invalid bundle manifest: %w", err)
+	}
+	if m.Format != bundleFormat {
+		return nil, nil, fmt.Errorf("unsupported bundle format %d", m.Format)
+	}
+
+	files := make(map[string][]byte, len(m.Files))
+	for _, bf := range m.Files {
+		if !fs.ValidPath(bf.Path) {
+			return nil, nil, fmt.Errorf("invalid path in bundle: %s", bf.Path)
+		}
+		b, err := readEntry(bf.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+		sum := sha256.Sum256(b)
+		if hex.EncodeToString(sum[:]) != bf.Sha256 || int64(len(b)) != bf.Size {
+			return nil, nil, fmt.Errorf("checksum mismatch for %s", bf.Path)
+		}
+		files[bf.Path] = b
+	}
+	if _, ok := files["project/project.json"]; !ok {
+		return nil, nil, errors.New("bundle has no project")
+	}
+	return &m, files, nil
+}
+
+// ImportProjectBundle adds the project of a .hyuga bundle to the workspace,
+// asking for the bundle when file is empty. The project gets a new ID if
+// one with the same ID already exists, and its machotes are merged into
+// the local library by hash.
+func (a *App) ImportProjectBundle(file string) (*Project, error) {
+	var err error
+	if file == "" {
+		file, err = runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+			Title:   "Importar proyecto",
+			Filters: []runtime.FileFilter{{DisplayName: "Hyuga (*.hyuga)", Pattern: "*.hyuga"}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if file == "" {
+			return nil, errors.New("no file path provided")
+		}
+	}
+
+	m, files, err := readBundle(file)
+	if err != nil {
+		return nil, err
+	}
+	var proj Project
+	if err := json.Unmarshal(files["project/project.json"], &proj); err != nil {
+		return nil, fmt.Errorf("invalid project JSON %w", err)
+	}
+
+	base, err := a.workspaceDir()
+	if err != nil {
+		return nil, err
+	}
+	// IDs end up in paths, so anything but a UUID is replaced
+	if uuid.Validate(proj.Id) != nil {
+		proj.Id = uuid.NewString()
+	}
+	if _, err := os.Stat(projectDir(base, proj.Id)); err == nil {
+		proj.Id = uuid.NewString()
+	}
+	seen := map[string]bool{}
+	for i := range proj.Assets {
+		as := &proj.Assets[i]
+		if uuid.Validate(strings.TrimPrefix(as.ID, "asset-")) != nil || seen[as.ID] {
+			as.ID = concat("asset-", uuid.NewString())
+		}
+		seen[as.ID] = true
+	}
+
+	lib, err := loadModelLibrary(base)
+	if err != nil {
+		return nil, err
+	}
+	local := make(map[string]string, len(m.Machotes))
+	for _, mc := range m.Machotes {
+		b, ok := files[mc.Path]
+		if !ok {
+			return nil, fmt.Errorf("bundle is missing machote %s", mc.Path)
+		}
+		if local[mc.Path], err = lib.add(mc.Label, b); err != nil {
+			return nil, err
+		}
+	}
+	if err := lib.save(); err != nil {
+		return nil, err
+	}
+	relink := func(ref string) string {
+		if p, ok := local[ref]; ok {
+			return p
+		}
+		return ref
+	}
+	proj.DefaultModel = relink(proj.DefaultModel)
+	pubs, err := readPublications(base)
+	if err != nil {
+		return nil, err
+	}
+	for i := range proj.Assets {
+		as := &proj.Assets[i]
+		as.Model = relink(as.Model)
+		// publications of another workspace are unknown here, the name stays
+		if !slices.ContainsFunc(pubs, func(p Publication) bool { return p.Id == as.PublicationId }) {
+			as.PublicationId = ""
+		}
+	}
+
+	// unpack next to the final folder so a failed import leaves nothing behind
+	dst := projectDir(base, proj.Id)
+	tmp := dst + ".import"
+	if err := os.MkdirAll(tmp, 0755); err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+	for name, b := range files {
+		rel, ok := strings.CutPrefix(name, "project/")
+		if !ok || rel == "project.json" {
+			continue
+		}
+		target := filepath.Join(tmp, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(target, b, 0644); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		return nil, fmt.Errorf("failed to import project: %w", err)
+	}
+
+	p := &proj
+	migrateTimestamps(p)
+	if err := writeProject(base, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
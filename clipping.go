@@ -0,0 +1,217 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/signintech/gopdf"
+)
+
+// Clipping metadata says where and when an asset was published. It is
+// shown in the caption of the cutout page and indexed for search.
+
+type MediaType string
+
+const (
+	MEDIA_PRINT    MediaType = "print"
+	MEDIA_ONLINE   MediaType = "online"
+	MEDIA_MAGAZINE MediaType = "magazine"
+)
+
+var mediaTypes = []MediaType{MEDIA_PRINT, MEDIA_ONLINE, MEDIA_MAGAZINE}
+
+const (
+	maxNameLength     = 200
+	maxHeadlineLength = 500
+)
+
+// cleanClipping trims the clipping metadata of as and checks it.
+func (as *AssetMetadata) cleanClipping() error {
+	as.Publication = strings.TrimSpace(as.Publication)
+	as.Headline = strings.TrimSpace(as.Headline)
+	as.Byline = strings.TrimSpace(as.Byline)
+	as.URL = strings.TrimSpace(as.URL)
+	as.EditionDate = strings.TrimSpace(as.EditionDate)
+
+	for _, f := range []struct {
+		name, value string
+		max         int
+	}{
+		{"publication", as.Publication, maxNameLength},
+		{"byline", as.Byline, maxNameLength},
+		{"headline", as.Headline, maxHeadlineLength},
+	} {
+		if utf8.RuneCountInString(f.value) > f.max {
+			return fmt.Errorf("%s is longer than %d characters", f.name, f.max)
+		}
+	}
+	if as.MediaType != "" && !slices.Contains(mediaTypes, as.MediaType) {
+		return fmt.Errorf("unknown media type %q", as.MediaType)
+	}
+	if as.EditionDate != "" {
+		if _, err := time.Parse(time.DateOnly, as.EditionDate); err != nil {
+			return fmt.Errorf("edition date must be YYYY-MM-DD: %w", err)
+		}
+	}
+	if as.MediaType == MEDIA_ONLINE && as.URL == "" {
+		return errors.New("online clippings need their URL")
+	}
+	if as.URL != "" {
+		u, err := url.Parse(as.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid URL %q, expected an http or https address", as.URL)
+		}
+	}
+	return nil
+}
+
+// formatDate writes a YYYY-MM-DD date the way the report's language does.
+func formatDate(lang, date string) string {
+	t, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return date
+	}
+	month := tr(lang, strings.ToLower(t.Month().String()))
+	return fmt.Sprintf(tr(lang, "date"), t.Day(), month, t.Year())
+}
+
+// clippingCaption describes where as was published, leaving out what is
+// unknown, followed by the custom fields meant for captions.
+func clippingCaption(lang string, as AssetMetadata, schema []CustomField) string {
+	var parts []string
+	add := func(s string) {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	add(as.Publication)
+	if as.EditionDate != "" {
+		add(formatDate(lang, as.EditionDate))
+	}
+	add(as.Section)
+	if as.PageNumber != "" {
+		add(fmt.Sprintf(tr(lang, "page"), as.PageNumber))
+	}
+	if as.Headline != "" {
+		add(concat("“", as.Headline, "”"))
+	}
+	if as.Byline != "" {
+		add(fmt.Sprintf(tr(lang, "byline"), as.Byline))
+	}
+	add(as.URL)
+	parts = append(parts, fieldCaption(lang, schema, as.Fields)...)
+	return strings.Join(parts, " · ")
+}
+
+const captionH = 16.0
+
+// maxCaptionLines bounds how much of the cutout page a caption takes.
+const maxCaptionLines = 3
+
+// captionLine is a line of the caption at the foot of a cutout page.
+type captionLine struct {
+	text  string
+	align int
+}
+
+// captionLines wraps the clipping caption to the page, left aligned, and
+// adds the continuation caption right aligned below it.
+func captionLines(pdf *gopdf.GoPdf, ps pageSetup, clipping, continuation string) ([]captionLine, error) {
+	if err := pdf.SetFont("times", "", 10); err != nil {
+		return nil, err
+	}
+	var lines []captionLine
+	if clipping != "" {
+		wrapped, err := pdf.SplitTextWithWordWrap(clipping, ps.W-2*ps.Margin)
+		if err != nil {
+			return nil, err
+		}
+		if len(wrapped) > maxCaptionLines {
+			wrapped = wrapped[:maxCaptionLines]
+			wrapped[maxCaptionLines-1] += "…"
+		}
+		for _, l := range wrapped {
+			lines = append(lines, captionLine{text: l, align: gopdf.Left | gopdf.Middle})
+		}
+	}
+	if continuation != "" {
+		lines = append(lines, captionLine{text: continuation, align: gopdf.Right | gopdf.Middle})
+	}
+	return lines, nil
+}
+
+// drawCaption writes lines at the foot of the page.
+func drawCaption(pdf *gopdf.GoPdf, lines []captionLine, ps pageSetup) error {
+	if err := pdf.SetFont("times", "", 10); err != nil {
+		return err
+	}
+	y := ps.H - ps.Margin - captionH*float64(len(lines))
+	for _, l := range lines {
+		pdf.SetXY(ps.Margin, y)
+		if err := pdf.CellWithOption(&gopdf.Rect{W: ps.W - 2*ps.Margin, H: captionH}, l.text, gopdf.CellOption{Align: l.align}); err != nil {
+			return err
+		}
+		y += captionH
+	}
+	return nil
+}
+
+// UpdateAsset replaces the descriptive fields of an asset: page, section,
+// machote, clipping metadata and custom fields. Images and their edits
+// have setters of their own.
+func (a *App) UpdateAsset(projectId string, upd AssetMetadata) error {
+	if projectId == "" || upd.ID == "" {
+		return fmt.Errorf("required project or asset ID not found")
+	}
+	if err := upd.cleanClipping(); err != nil {
+		return err
+	}
+	base, err := a.workspaceDir()
+	if err != nil {
+		return err
+	}
+	proj, err := readProject(base, projectId)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(proj.Assets, func(as AssetMetadata) bool { return as.ID == upd.ID })
+	if i == -1 {
+		return fmt.Errorf("asset with ID %s not found in project", upd.ID)
+	}
+	if upd.Fields, err = cleanFieldValues(proj.Fields, upd.Fields); err != nil {
+		return err
+	}
+	pubModel, err := applyPublication(base, &upd)
+	if err != nil {
+		return err
+	}
+	if upd.Model == "" || upd.Model == "empty" {
+		upd.Model = cmp.Or(pubModel, proj.DefaultModel)
+	}
+	if upd.Model != "" && upd.Model != proj.Assets[i].Model {
+		if err := checkImageFile("model", upd.Model); err != nil {
+			return err
+		}
+	}
+
+	as := &proj.Assets[i]
+	as.PageNumber = upd.PageNumber
+	as.Section = upd.Section
+	as.Model = upd.Model
+	as.PublicationId = upd.PublicationId
+	as.Publication = upd.Publication
+	as.EditionDate = upd.EditionDate
+	as.Headline = upd.Headline
+	as.Byline = upd.Byline
+	as.MediaType = upd.MediaType
+	as.URL = upd.URL
+	as.Fields = upd.Fields
+	as.UpdatedAt = timestamp()
+	return writeProject(base, proj)
+}
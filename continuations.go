@@ -0,0 +1,160 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Articles often start on one page and continue on another. The first page
+// is the asset's own Sheet, PageNumber and cutout; every further page is a
+// Continuation with its sheet and, usually, the cutout of the part of the
+// article printed on it. Reports show every sheet page, then the cutouts
+// one after the other with "continúa en pág." captions between them.
+
+type Continuation struct {
+	Sheet      string `json:"sheet"`
+	PageNumber string `json:"pageNumber"`
+	// Cutout may be empty when the page only shows, say, a photo of the
+	// article and there is nothing to cut.
+	Cutout           string      `json:"cutout,omitempty"`
+	SheetEdits       []ImageEdit `json:"sheetEdits,omitempty"`
+	CutoutEdits      []ImageEdit `json:"cutoutEdits,omitempty"`
+	SheetRedactions  []Redaction `json:"sheetRedactions,omitempty"`
+	CutoutRedactions []Redaction `json:"cutoutRedactions,omitempty"`
+}
+
+func (c Continuation) validate() error {
+	if strings.TrimSpace(c.PageNumber) == "" {
+		return errors.New("page number is required")
+	}
+	for i, e := range slices.Concat(c.SheetEdits, c.CutoutEdits) {
+		if err := e.validate(); err != nil {
+			return fmt.Errorf("edit %d: %w", i+1, err)
+		}
+	}
+	for i, r := range slices.Concat(c.SheetRedactions, c.CutoutRedactions) {
+		if err := r.validate(); err != nil {
+			return fmt.Errorf("redaction %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// ingestContinuations validates cs and readies their images to store.
+func ingestContinuations(cs []Continuation, opts ingestOptions) error {
+	for i := range cs {
+		c := &cs[i]
+		if err := c.validate(); err != nil {
+			return fmt.Errorf("continuation %d: %w", i+1, err)
+		}
+		field := fmt.Sprintf("continuation %d ", i+1)
+		var err error
+		if c.Sheet, _, err = ingestImageRef(field+string(SHEET), c.Sheet, opts); err != nil {
+			return err
+		}
+		if c.Cutout != "" {
+			if c.Cutout, _, err = ingestImageRef(field+string(CUTOUT), c.Cutout, opts); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// pages returns as followed by an asset for each of its continuations, so
+// assetImage renders the images of every page alike.
+func (as AssetMetadata) pages() []AssetMetadata {
+	res := []AssetMetadata{as}
+	for _, c := range as.Continuations {
+		res = append(res, AssetMetadata{
+			ID:               as.ID,
+			Sheet:            c.Sheet,
+			Cutout:           c.Cutout,
+			PageNumber:       c.PageNumber,
+			Model:            as.Model,
+			SheetEdits:       c.SheetEdits,
+			CutoutEdits:      c.CutoutEdits,
+			SheetRedactions:  c.SheetRedactions,
+			CutoutRedactions: c.CutoutRedactions,
+			Enhance:          as.Enhance,
+		})
+	}
+	return res
+}
+
+// images returns the references of every image stored for as.
+func (as AssetMetadata) images() []string {
+	var refs []string
+	for _, p := range as.pages() {
+		refs = append(refs, p.Sheet)
+		if p.Cutout != "" {
+			refs = append(refs, p.Cutout)
+		}
+	}
+	return refs
+}
+
+// continuationCaption returns the caption of the i-th of pages, naming the
+// pages the article comes from and continues on.
+func continuationCaption(lang string, pages []AssetMetadata, i int) string {
+	var parts []string
+	if i > 0 {
+		parts = append(parts, fmt.Sprintf(tr(lang, "continued_from"), pages[i-1].PageNumber))
+	}
+	if i < len(pages)-1 {
+		parts = append(parts, fmt.Sprintf(tr(lang, "continued_on"), pages[i+1].PageNumber))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// SetContinuations replaces the further pages of an asset.
+func (a *App) SetContinuations(projectId string, assetId string, continuations []Continuation) error {
+	if projectId == "" || assetId == "" {
+		return fmt.Errorf("required project or asset ID not found")
+	}
+	settings, err := currentSettings()
+	if err != nil {
+		return err
+	}
+	if err := ingestContinuations(continuations, ingestOptionsFrom(settings)); err != nil {
+		return err
+	}
+
+	base, err := a.workspaceDir()
+	if err != nil {
+		return err
+	}
+	proj, err := readProject(base, projectId)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(proj.Assets, func(as AssetMetadata) bool { return as.ID == assetId })
+	if i == -1 {
+		return fmt.Errorf("asset with ID %s not found in project", assetId)
+	}
+	// new images of an asset baked at upload are baked alike, those of any
+	// other asset are enhanced when rendering
+	if baked := proj.Assets[i].Baked; baked != "" {
+		stored := proj.Assets[i].images()
+		for j := range continuations {
+			c := &continuations[j]
+			for _, ref := range []*string{&c.Sheet, &c.Cutout} {
+				if *ref == "" || slices.Contains(stored, *ref) {
+					continue
+				}
+				if *ref, err = bakeEnhance(*ref, baked, settings.ImageQuality); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	proj.Assets[i].Continuations = continuations
+	proj.Assets[i].UpdatedAt = timestamp()
+	if err := writeProject(base, proj); err != nil {
+		return err
+	}
+	cacheThumbnails(base, proj.Assets[i].images()...)
+	return nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"slices"
+)
+
+// An asset can define its cutout as regions of the sheet instead of a
+// second photo. Regions are normalized to the sheet after its edits, and
+// are cut from it at full resolution when rendering. Several regions, as
+// for an article laid out in separate columns, are stacked top to bottom.
+
+// normalized reports whether r is a non-empty region inside the unit square.
+func (r Region) normalized() bool {
+	return r.W > 0 && r.H > 0 && r.X >= 0 && r.Y >= 0 && r.X+r.W <= 1 && r.Y+r.H <= 1
+}
+
+func validateRegions(regions []Region) error {
+	if slices.ContainsFunc(regions, func(r Region) bool { return !r.normalized() }) {
+		return errors.New("cutout regions must be non-empty and inside the sheet")
+	}
+	return nil
+}
+
+// cutoutFromRegions stacks the regions of sheet on a white background,
+// each centered on the width of the widest one.
+func cutoutFromRegions(sheet image.Image, regions []Region) image.Image {
+	parts := make([]image.Image, len(regions))
+	w, h := 0, 0
+	for i, r := range regions {
+		parts[i] = crop(sheet, r)
+		w = max(w, parts[i].Bounds().Dx())
+		h += parts[i].Bounds().Dy()
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(paper), image.Point{}, draw.Src)
+	y := 0
+	for _, p := range parts {
+		pb := p.Bounds()
+		x := (w - pb.Dx()) / 2
+		draw.Draw(dst, image.Rect(x, y, x+pb.Dx(), y+pb.Dy()), p, pb.Min, draw.Src)
+		y += pb.Dy()
+	}
+	return dst
+}
+
+// SetCutoutRegions defines the cutout of an asset as regions of its sheet.
+// No regions go back to the uploaded cutout, which must then exist.
+// highlight adds the regions to the highlights of the sheet page.
+func (a *App) SetCutoutRegions(projectId string, assetId string, regions []Region, highlight bool) error {
+	if projectId == "" || assetId == "" {
+		return fmt.Errorf("required project or asset ID not found")
+	}
+	if err := validateRegions(regions); err != nil {
+		return err
+	}
+
+	base, err := a.workspaceDir()
+	if err != nil {
+		return err
+	}
+	proj, err := readProject(base, projectId)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(proj.Assets, func(as AssetMetadata) bool { return as.ID == assetId })
+	if i == -1 {
+		return fmt.Errorf("asset with ID %s not found in project", assetId)
+	}
+	as := &proj.Assets[i]
+	if len(regions) == 0 && as.Cutout == "" {
+		return errors.New("the asset has no uploaded cutout to fall back to")
+	}
+	as.CutoutRegions = regions
+	as.HighlightCutout = highlight && len(regions) > 0
+	as.UpdatedAt = timestamp()
+	return writeProject(base, proj)
+}
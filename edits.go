@@ -0,0 +1,364 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"slices"
+)
+
+// Edits are stored as a list on the asset and replayed on the original
+// image whenever it is rendered, so they can be changed or undone at any
+// time without losing pixels. Coordinates are normalized to the image the
+// edit applies to: 0,0 is its top left corner and 1,1 its bottom right.
+
+type EditOp string
+
+const (
+	EDIT_ROTATE      EditOp = "rotate"      // quarter turns clockwise, Angle is 90, 180 or 270
+	EDIT_STRAIGHTEN  EditOp = "straighten"  // Angle degrees clockwise, up to 45 either way
+	EDIT_CROP        EditOp = "crop"        // keeps Crop
+	EDIT_PERSPECTIVE EditOp = "perspective" // maps Corners to an upright rectangle
+)
+
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+type ImageEdit struct {
+	Op    EditOp  `json:"op"`
+	Angle float64 `json:"angle,omitempty"`
+	Crop  *Region `json:"crop,omitempty"`
+	// Corners of the page in the photo: top left, top right, bottom right
+	// and bottom left.
+	Corners []Point `json:"corners,omitempty"`
+}
+
+const maxStraighten = 45.0
+
+// paper fills what straightening uncovers.
+var paper = color.RGBA{255, 255, 255, 255}
+
+func (e ImageEdit) validate() error {
+	inside := func(p Point) bool { return p.X >= 0 && p.X <= 1 && p.Y >= 0 && p.Y <= 1 }
+	switch e.Op {
+	case EDIT_ROTATE:
+		if !slices.Contains([]float64{90, 180, 270}, e.Angle) {
+			return fmt.Errorf("rotation must be 90, 180 or 270 degrees, not %v", e.Angle)
+		}
+	case EDIT_STRAIGHTEN:
+		if math.Abs(e.Angle) > maxStraighten {
+			return fmt.Errorf("straightening is limited to %v degrees", maxStraighten)
+		}
+	case EDIT_CROP:
+		r := e.Crop
+		if r == nil || r.W <= 0 || r.H <= 0 || !inside(Point{r.X, r.Y}) || !inside(Point{r.X + r.W, r.Y + r.H}) {
+			return errors.New("crop must be a non-empty region inside the image")
+		}
+	case EDIT_PERSPECTIVE:
+		if len(e.Corners) != 4 || slices.ContainsFunc(e.Corners, func(p Point) bool { return !inside(p) }) {
+			return errors.New("perspective needs four corners inside the image")
+		}
+		if !convex(e.Corners) {
+			return errors.New("perspective corners must form a convex shape, clockwise from the top left")
+		}
+	default:
+		return fmt.Errorf("unknown edit %q", e.Op)
+	}
+	return nil
+}
+
+// convex reports whether the quad turns the same way, clockwise on screen,
+// at every corner.
+func convex(q []Point) bool {
+	for i := range q {
+		a, b, c := q[i], q[(i+1)%4], q[(i+2)%4]
+		if (b.X-a.X)*(c.Y-b.Y)-(b.Y-a.Y)*(c.X-b.X) <= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// SetAssetEdits replaces the edit list of the sheet or cutout of an asset.
+// An empty list restores the original image.
+func (a *App) SetAssetEdits(projectId string, assetId string, photo PhotoType, edits []ImageEdit) error {
+	if projectId == "" || assetId == "" {
+		return fmt.Errorf("required project or asset ID not found")
+	}
+	for i, e := range edits {
+		if err := e.validate(); err != nil {
+			return fmt.Errorf("edit %d: %w", i+1, err)
+		}
+	}
+
+	base, err := a.workspaceDir()
+	if err != nil {
+		return err
+	}
+	proj, err := readProject(base, projectId)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(proj.Assets, func(as AssetMetadata) bool { return as.ID == assetId })
+	if i == -1 {
+		return fmt.Errorf("asset with ID %s not found in project", assetId)
+	}
+	as := &proj.Assets[i]
+	switch photo {
+	case SHEET:
+		as.SheetEdits = edits
+	case CUTOUT:
+		as.CutoutEdits = edits
+	default:
+		return fmt.Errorf("unknown photo type %q", photo)
+	}
+	as.UpdatedAt = timestamp()
+	return writeProject(base, proj)
+}
+
+// PreviewAssetImage returns the sheet or cutout of an asset as it will be
+// exported, with its edits and, if the project asks for it, its
+// redactions applied, as a data URL.
+func (a *App) PreviewAssetImage(projectId string, assetId string, photo PhotoType) (string, error) {
+	base, err := a.workspaceDir()
+	if err != nil {
+		return "", err
+	}
+	proj, err := readProject(base, projectId)
+	if err != nil {
+		return "", err
+	}
+	i := slices.IndexFunc(proj.Assets, func(as AssetMetadata) bool { return as.ID == assetId })
+	if i == -1 {
+		return "", fmt.Errorf("asset with ID %s not found in project", assetId)
+	}
+	img, err := assetImage(proj.Assets[i], photo, renderOptionsFor(base, *proj))
+	if err != nil {
+		return "", err
+	}
+	settings, err := currentSettings()
+	if err != nil {
+		return "", err
+	}
+	b, mime, err := encodeImage(img, settings.ImageQuality)
+	if err != nil {
+		return "", err
+	}
+	return dataURL(mime, b), nil
+}
+
+// renderOptions are the project settings that shape the images of its
+// assets in a report.
+type renderOptions struct {
+	base     string // workspace, for the enhancement cache
+	redacted bool
+	enhance  EnhancePreset // the project default
+}
+
+func renderOptionsFor(base string, proj Project) renderOptions {
+	return renderOptions{base: base, redacted: proj.RedactedExport, enhance: proj.Enhance}
+}
+
+// assetImage decodes the sheet or cutout of as enhanced, with its edits
+// applied, and its redactions burned in when the project asks for it. A
+// cutout defined by regions is cut from the finished sheet.
+func assetImage(as AssetMetadata, photo PhotoType, ro renderOptions) (image.Image, error) {
+	ref, edits, redactions := as.Sheet, as.SheetEdits, as.SheetRedactions
+	if photo == CUTOUT {
+		ref, edits, redactions = as.Cutout, as.CutoutEdits, as.CutoutRedactions
+	}
+	var img image.Image
+	var err error
+	if photo == CUTOUT && len(as.CutoutRegions) > 0 {
+		sheet, err := assetImage(as, SHEET, ro)
+		if err != nil {
+			return nil, err
+		}
+		img = cutoutFromRegions(sheet, as.CutoutRegions)
+	} else if img, err = enhancedImage(ro.base, ref, as.enhancePreset(ro.enhance)); err != nil {
+		return nil, fmt.Errorf("asset %s: cannot decode %s: %w", as.ID, photo, err)
+	}
+	if img, err = applyEdits(img, edits); err != nil {
+		return nil, fmt.Errorf("asset %s: %s %w", as.ID, photo, err)
+	}
+	if ro.redacted {
+		img = redact(img, redactions)
+	}
+	return img, nil
+}
+
+// applyEdits replays edits on img in order.
+func applyEdits(img image.Image, edits []ImageEdit) (image.Image, error) {
+	for i, e := range edits {
+		if err := e.validate(); err != nil {
+			return nil, fmt.Errorf("edit %d: %w", i+1, err)
+		}
+		switch e.Op {
+		case EDIT_ROTATE:
+			// EXIF orientations 6, 3 and 8 are the clockwise quarter turns
+			img = orient(img, map[float64]int{90: 6, 180: 3, 270: 8}[e.Angle])
+		case EDIT_STRAIGHTEN:
+			img = straighten(img, e.Angle)
+		case EDIT_CROP:
+			img = crop(img, *e.Crop)
+		case EDIT_PERSPECTIVE:
+			img = unwarp(img, e.Corners)
+		}
+	}
+	return img, nil
+}
+
+func crop(img image.Image, r Region) image.Image {
+	b := img.Bounds()
+	w, h := float64(b.Dx()), float64(b.Dy())
+	px := image.Rect(
+		b.Min.X+int(math.Round(r.X*w)), b.Min.Y+int(math.Round(r.Y*h)),
+		b.Min.X+int(math.Round((r.X+r.W)*w)), b.Min.Y+int(math.Round((r.Y+r.H)*h)),
+	).Intersect(b)
+	if px.Empty() {
+		// a crop thinner than a pixel still keeps one
+		px = image.Rect(px.Min.X, px.Min.Y, px.Min.X+1, px.Min.Y+1).Intersect(b)
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, px.Dx(), px.Dy()))
+	for y := range px.Dy() {
+		for x := range px.Dx() {
+			dst.Set(x, y, img.At(px.Min.X+x, px.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+// bilinear samples src at the real pixel position x, y. ok is false
+// outside the image.
+func bilinear(src *image.RGBA, x, y float64) (c color.RGBA, ok bool) {
+	b := src.Bounds()
+	x -= 0.5
+	y -= 0.5
+	if x < -0.5 || y < -0.5 || x > float64(b.Dx())-0.5 || y > float64(b.Dy())-0.5 {
+		return c, false
+	}
+	x0, y0 := int(math.Floor(x)), int(math.Floor(y))
+	fx, fy := x-float64(x0), y-float64(y0)
+	at := func(x, y int) color.RGBA {
+		x = min(max(x, 0), b.Dx()-1)
+		y = min(max(y, 0), b.Dy()-1)
+		return src.RGBAAt(b.Min.X+x, b.Min.Y+y)
+	}
+	c00, c10, c01, c11 := at(x0, y0), at(x0+1, y0), at(x0, y0+1), at(x0+1, y0+1)
+	mix := func(a, b, c, d uint8) uint8 {
+		top := float64(a)*(1-fx) + float64(b)*fx
+		bottom := float64(c)*(1-fx) + float64(d)*fx
+		return uint8(math.Round(top*(1-fy) + bottom*fy))
+	}
+	return color.RGBA{
+		mix(c00.R, c10.R, c01.R, c11.R),
+		mix(c00.G, c10.G, c01.G, c11.G),
+		mix(c00.B, c10.B, c01.B, c11.B),
+		mix(c00.A, c10.A, c01.A, c11.A),
+	}, true
+}
+
+// straighten rotates img by deg degrees clockwise around its center,
+// keeping its size and filling the uncovered corners with paper.
+func straighten(img image.Image, deg float64) image.Image {
+	if deg == 0 {
+		return img
+	}
+	src := toRGBA(img)
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	cx, cy := float64(w)/2, float64(h)/2
+	sin, cos := math.Sincos(deg * math.Pi / 180)
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			// rotate the destination pixel back to find its source
+			dx, dy := float64(x)+0.5-cx, float64(y)+0.5-cy
+			sx := cx + dx*cos + dy*sin
+			sy := cy - dx*sin + dy*cos
+			c, ok := bilinear(src, sx, sy)
+			if !ok {
+				c = paper
+			}
+			dst.SetRGBA(x, y, c)
+		}
+	}
+	return dst
+}
+
+// unwarp maps the quad q of img onto an upright rectangle as large as the
+// longest of its opposite sides.
+func unwarp(img image.Image, q []Point) image.Image {
+	src := toRGBA(img)
+	iw, ih := float64(src.Bounds().Dx()), float64(src.Bounds().Dy())
+	px := make([]Point, 4)
+	for i, p := range q {
+		px[i] = Point{p.X * iw, p.Y * ih}
+	}
+	dist := func(a, b Point) float64 { return math.Hypot(b.X-a.X, b.Y-a.Y) }
+	w := int(math.Round(max(dist(px[0], px[1]), dist(px[3], px[2]))))
+	h := int(math.Round(max(dist(px[0], px[3]), dist(px[1], px[2]))))
+	w, h = max(w, 1), max(h, 1)
+
+	fw, fh := float64(w), float64(h)
+	m, ok := homography([]Point{{0, 0}, {fw, 0}, {fw, fh}, {0, fh}}, px)
+	if !ok {
+		return img
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			fx, fy := float64(x)+0.5, float64(y)+0.5
+			d := m[6]*fx + m[7]*fy + 1
+			sx := (m[0]*fx + m[1]*fy + m[2]) / d
+			sy := (m[3]*fx + m[4]*fy + m[5]) / d
+			c, ok := bilinear(src, sx, sy)
+			if !ok {
+				c = paper
+			}
+			dst.SetRGBA(x, y, c)
+		}
+	}
+	return dst
+}
+
+// homography returns the projective transform, as the first eight entries
+// of its 3x3 matrix, taking each point of from to the same point of to.
+func homography(from, to []Point) ([8]float64, bool) {
+	var a [8][9]float64
+	for i := range 4 {
+		x, y, u, v := from[i].X, from[i].Y, to[i].X, to[i].Y
+		a[2*i] = [9]float64{x, y, 1, 0, 0, 0, -u * x, -u * y, u}
+		a[2*i+1] = [9]float64{0, 0, 0, x, y, 1, -v * x, -v * y, v}
+	}
+	// Gaussian elimination with partial pivoting
+	for col := range 8 {
+		pivot := col
+		for r := col + 1; r < 8; r++ {
+			if math.Abs(a[r][col]) > math.Abs(a[pivot][col]) {
+				pivot = r
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return [8]float64{}, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		for r := range 8 {
+			if r == col {
+				continue
+			}
+			f := a[r][col] / a[col][col]
+			for c := col; c < 9; c++ {
+				a[r][c] -= f * a[col][c]
+			}
+		}
+	}
+	var m [8]float64
+	for i := range 8 {
+		m[i] = a[i][8] / a[i][i]
+	}
+	return m, true
+}
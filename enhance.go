@@ -0,0 +1,485 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+)
+
+// Enhancement turns phone photos of newsprint into clean, scanner-like
+// images. A preset is a fixed sequence of steps. Projects pick a default
+// preset and assets may override it; results are cached by the hash of
+// the original image, so only the first render of an image pays for it.
+
+type EnhanceStep string
+
+const (
+	ENHANCE_AUTO_LEVELS    EnhanceStep = "auto_levels"
+	ENHANCE_WHITE_BALANCE  EnhanceStep = "white_balance"
+	ENHANCE_GRAYSCALE      EnhanceStep = "grayscale"
+	ENHANCE_THRESHOLD      EnhanceStep = "threshold"
+	ENHANCE_SHARPEN        EnhanceStep = "sharpen"
+	ENHANCE_REMOVE_SHADOWS EnhanceStep = "remove_shadows"
+)
+
+type EnhancePreset string
+
+const (
+	PRESET_INHERIT   EnhancePreset = ""          // use the project preset
+	PRESET_NONE      EnhancePreset = "none"      // the photo as taken
+	PRESET_COLOR     EnhancePreset = "color"     // balanced colors and contrast
+	PRESET_DOCUMENT  EnhancePreset = "document"  // color, without shadows and yellowing
+	PRESET_GRAYSCALE EnhancePreset = "grayscale" // a gray scan
+	PRESET_SCAN      EnhancePreset = "scan"      // a black and white scan
+)
+
+var enhancePresets = map[EnhancePreset][]EnhanceStep{
+	PRESET_NONE:      {},
+	PRESET_COLOR:     {ENHANCE_WHITE_BALANCE, ENHANCE_AUTO_LEVELS, ENHANCE_SHARPEN},
+	PRESET_DOCUMENT:  {ENHANCE_REMOVE_SHADOWS, ENHANCE_WHITE_BALANCE, ENHANCE_AUTO_LEVELS, ENHANCE_SHARPEN},
+	PRESET_GRAYSCALE: {ENHANCE_REMOVE_SHADOWS, ENHANCE_GRAYSCALE, ENHANCE_AUTO_LEVELS, ENHANCE_SHARPEN},
+	PRESET_SCAN:      {ENHANCE_REMOVE_SHADOWS, ENHANCE_GRAYSCALE, ENHANCE_THRESHOLD},
+}
+
+// enhanceVersion is part of the cache key. Bump it when a step changes so
+// stale results are not reused.
+const enhanceVersion = 1
+
+type EnhancePresetInfo struct {
+	Preset EnhancePreset `json:"preset"`
+	Steps  []EnhanceStep `json:"steps"`
+}
+
+func (a *App) ListEnhancePresets() []EnhancePresetInfo {
+	res := make([]EnhancePresetInfo, 0, len(enhancePresets))
+	for p, steps := range enhancePresets {
+		res = append(res, EnhancePresetInfo{Preset: p, Steps: steps})
+	}
+	// from the lightest touch to the heaviest
+	sort.Slice(res, func(i, j int) bool {
+		if len(res[i].Steps) != len(res[j].Steps) {
+			return len(res[i].Steps) < len(res[j].Steps)
+		}
+		return res[i].Preset < res[j].Preset
+	})
+	return res
+}
+
+func (p EnhancePreset) validate(inherit bool) error {
+	if _, ok := enhancePresets[p]; ok || inherit && p == PRESET_INHERIT {
+		return nil
+	}
+	return fmt.Errorf("unknown enhancement preset %q", p)
+}
+
+// enhancePreset is the preset the images of as are rendered with.
+func (as AssetMetadata) enhancePreset(project EnhancePreset) EnhancePreset {
+	if as.Enhance != PRESET_INHERIT {
+		return as.Enhance
+	}
+	return project
+}
+
+// enhance runs the steps of preset on img.
+func enhance(img image.Image, preset EnhancePreset) image.Image {
+	steps := enhancePresets[preset]
+	if len(steps) == 0 {
+		return img
+	}
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
+	for _, s := range steps {
+		switch s {
+		case ENHANCE_AUTO_LEVELS:
+			autoLevels(dst)
+		case ENHANCE_WHITE_BALANCE:
+			whiteBalance(dst)
+		case ENHANCE_GRAYSCALE:
+			grayscale(dst)
+		case ENHANCE_THRESHOLD:
+			adaptiveThreshold(dst)
+		case ENHANCE_SHARPEN:
+			unsharpMask(dst, 1, 0.6)
+		case ENHANCE_REMOVE_SHADOWS:
+			removeShadows(dst)
+		}
+	}
+	return dst
+}
+
+func clamp8(v float64) uint8 {
+	return uint8(min(max(math.Round(v), 0), 255))
+}
+
+func luma(r, g, b uint8) float64 {
+	return 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+}
+
+// autoLevels stretches every channel so its darkest and brightest 0.5%
+// of pixels become black and white.
+func autoLevels(img *image.RGBA) {
+	n := len(img.Pix) / 4
+	clip := n / 200
+	for c := range 3 {
+		var hist [256]int
+		for i := c; i < len(img.Pix); i += 4 {
+			hist[img.Pix[i]]++
+		}
+		lo, hi, sum := 0, 255, 0
+		for ; lo < 255; lo++ {
+			if sum += hist[lo]; sum > clip {
+				break
+			}
+		}
+		for sum = 0; hi > 0; hi-- {
+			if sum += hist[hi]; sum > clip {
+				break
+			}
+		}
+		if hi <= lo {
+			continue
+		}
+		scale := 255 / float64(hi-lo)
+		for i := c; i < len(img.Pix); i += 4 {
+			img.Pix[i] = clamp8((float64(img.Pix[i]) - float64(lo)) * scale)
+		}
+	}
+}
+
+// whiteBalance takes the brightest 5% of the image to be paper and scales
+// the channels so it turns neutral white, removing the yellow of old
+// newsprint and warm indoor light.
+func whiteBalance(img *image.RGBA) {
+	n := len(img.Pix) / 4
+	var hist [256]int
+	for i := 0; i < len(img.Pix); i += 4 {
+		hist[clamp8(luma(img.Pix[i], img.Pix[i+1], img.Pix[i+2]))]++
+	}
+	cut, sum := 255, 0
+	for ; cut > 0; cut-- {
+		if sum += hist[cut]; sum >= n/20 {
+			break
+		}
+	}
+	var total [3]float64
+	count := 0
+	for i := 0; i < len(img.Pix); i += 4 {
+		if int(clamp8(luma(img.Pix[i], img.Pix[i+1], img.Pix[i+2]))) >= cut {
+			for c := range 3 {
+				total[c] += float64(img.Pix[i+c])
+			}
+			count++
+		}
+	}
+	var scale [3]float64
+	for c := range 3 {
+		if total[c] == 0 {
+			return
+		}
+		scale[c] = 255 * float64(count) / total[c]
+	}
+	for i := 0; i < len(img.Pix); i += 4 {
+		for c := range 3 {
+			img.Pix[i+c] = clamp8(float64(img.Pix[i+c]) * scale[c])
+		}
+	}
+}
+
+func grayscale(img *image.RGBA) {
+	for i := 0; i < len(img.Pix); i += 4 {
+		y := clamp8(luma(img.Pix[i], img.Pix[i+1], img.Pix[i+2]))
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2] = y, y, y
+	}
+}
+
+// plane is one channel of an image as floats.
+type plane struct {
+	w, h int
+	v    []float64
+}
+
+func channel(img *image.RGBA, c int) plane {
+	b := img.Bounds()
+	p := plane{w: b.Dx(), h: b.Dy(), v: make([]float64, b.Dx()*b.Dy())}
+	for i := range p.v {
+		p.v[i] = float64(img.Pix[i*4+c])
+	}
+	return p
+}
+
+// boxBlur averages every value with its neighbours within r, horizontally
+// then vertically, in linear time whatever the radius.
+func (p plane) boxBlur(r int) plane {
+	return p.slide(r, true, func(w []float64, sum float64) float64 { return sum / float64(len(w)) }).
+		slide(r, false, func(w []float64, sum float64) float64 { return sum / float64(len(w)) })
+}
+
+// dilate takes the maximum within r, which erases dark print smaller than
+// the radius and leaves the paper.
+func (p plane) dilate(r int) plane {
+	return p.slide(r, true, func(w []float64, _ float64) float64 { return slices.Max(w) }).
+		slide(r, false, func(w []float64, _ float64) float64 { return slices.Max(w) })
+}
+
+// slide applies f to the window of radius r around every value along rows
+// or columns, passing the window and its sum.
+func (p plane) slide(r int, rows bool, f func(w []float64, sum float64) float64) plane {
+	out := plane{w: p.w, h: p.h, v: make([]float64, len(p.v))}
+	lines, length := p.h, p.w
+	at := func(line, i int) int { return line*p.w + i }
+	if !rows {
+		lines, length = p.w, p.h
+		at = func(line, i int) int { return i*p.w + line }
+	}
+	buf := make([]float64, length)
+	for l := range lines {
+		for i := range length {
+			buf[i] = p.v[at(l, i)]
+		}
+		sum := 0.0
+		lo, hi := 0, 0 // the window is buf[lo:hi]
+		for i := range length {
+			for ; hi < min(length, i+r+1); hi++ {
+				sum += buf[hi]
+			}
+			for ; lo < i-r; lo++ {
+				sum -= buf[lo]
+			}
+			out.v[at(l, i)] = f(buf[lo:hi], sum)
+		}
+	}
+	return out
+}
+
+// unsharpMask adds back amount times the detail lost to a blur of radius r.
+func unsharpMask(img *image.RGBA, r int, amount float64) {
+	for c := range 3 {
+		orig := channel(img, c)
+		blur := orig.boxBlur(r)
+		for i, v := range orig.v {
+			img.Pix[i*4+c] = clamp8(v + amount*(v-blur.v[i]))
+		}
+	}
+}
+
+// removeShadows estimates the paper under the print with a dilate and a
+// wide blur, then divides it out, flattening shadows and uneven light.
+// Light changes slowly, so the estimate is made on a shrunk copy, which
+// keeps the dilate cheap on large photos.
+func removeShadows(img *image.RGBA) {
+	b := img.Bounds()
+	f := max(1, min(b.Dx(), b.Dy())/256)
+	for c := range 3 {
+		orig := channel(img, c)
+		small := orig.shrink(f)
+		r := max(3, min(small.w, small.h)/40)
+		bg := small.dilate(r).boxBlur(2 * r)
+		for y := range orig.h {
+			for x := range orig.w {
+				i := y*orig.w + x
+				paper := bg.sample((float64(x)+0.5)/float64(f)-0.5, (float64(y)+0.5)/float64(f)-0.5)
+				img.Pix[i*4+c] = clamp8(255 * orig.v[i] / max(paper, 1))
+			}
+		}
+	}
+}
+
+// shrink averages blocks of f by f values.
+func (p plane) shrink(f int) plane {
+	if f == 1 {
+		return p
+	}
+	out := plane{w: (p.w + f - 1) / f, h: (p.h + f - 1) / f}
+	out.v = make([]float64, out.w*out.h)
+	n := make([]float64, len(out.v))
+	for y := range p.h {
+		for x := range p.w {
+			j := (y/f)*out.w + x/f
+			out.v[j] += p.v[y*p.w+x]
+			n[j]++
+		}
+	}
+	for j := range out.v {
+		out.v[j] /= n[j]
+	}
+	return out
+}
+
+// sample interpolates the value at x, y linearly, clamping to the edges.
+func (p plane) sample(x, y float64) float64 {
+	x = min(max(x, 0), float64(p.w-1))
+	y = min(max(y, 0), float64(p.h-1))
+	x0, y0 := int(x), int(y)
+	x1, y1 := min(x0+1, p.w-1), min(y0+1, p.h-1)
+	fx, fy := x-float64(x0), y-float64(y0)
+	top := p.v[y0*p.w+x0]*(1-fx) + p.v[y0*p.w+x1]*fx
+	bottom := p.v[y1*p.w+x0]*(1-fx) + p.v[y1*p.w+x1]*fx
+	return top*(1-fy) + bottom*fy
+}
+
+// adaptiveThreshold turns pixels black when they are clearly darker than
+// their surroundings and white otherwise, which copes with uneven light
+// where a single threshold would not.
+func adaptiveThreshold(img *image.RGBA) {
+	const t = 0.15
+	b := img.Bounds()
+	r := max(7, min(b.Dx(), b.Dy())/32)
+	lum := plane{w: b.Dx(), h: b.Dy(), v: make([]float64, b.Dx()*b.Dy())}
+	for i := range lum.v {
+		p := img.Pix[i*4:]
+		lum.v[i] = luma(p[0], p[1], p[2])
+	}
+	mean := lum.boxBlur(r)
+	for i, v := range lum.v {
+		y := uint8(255)
+		if v < mean.v[i]*(1-t) {
+			y = 0
+		}
+		img.Pix[i*4], img.Pix[i*4+1], img.Pix[i*4+2] = y, y, y
+	}
+}
+
+func enhanceCacheDir(base string) string {
+	return filepath.Join(base, "cache", "enhance")
+}
+
+// enhancedImage decodes ref with preset applied, going through the cache
+// in base when there is one.
+func enhancedImage(base, ref string, preset EnhancePreset) (image.Image, error) {
+	b, err := imageBytes(ref)
+	if err != nil {
+		return nil, err
+	}
+	if len(enhancePresets[preset]) == 0 {
+		img, _, err := image.Decode(bytes.NewReader(b))
+		return img, err
+	}
+	sum := sha256.Sum256(b)
+	file := filepath.Join(enhanceCacheDir(base), fmt.Sprintf("%s-%s-v%d.png", hex.EncodeToString(sum[:]), preset, enhanceVersion))
+	if base != "" {
+		if cached, err := os.ReadFile(file); err == nil {
+			if img, err := png.Decode(bytes.NewReader(cached)); err == nil {
+				return img, nil
+			}
+		}
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	img = enhance(img, preset)
+	if base != "" {
+		// a failed write only costs the next render the work again
+		if err := writeCache(file, img); err != nil {
+			fmt.Println(concat("could not cache enhanced image: ", err.Error()))
+		}
+	}
+	return img, nil
+}
+
+// bakeEnhance applies preset to the image ref for good, returning the new
+// image as a data URL.
+func bakeEnhance(ref string, preset EnhancePreset, quality int) (string, error) {
+	if len(enhancePresets[preset]) == 0 {
+		return ref, nil
+	}
+	img, err := enhancedImage("", ref, preset)
+	if err != nil {
+		return "", fmt.Errorf("cannot enhance image: %w", err)
+	}
+	b, mime, err := encodeImage(img, quality)
+	if err != nil {
+		return "", err
+	}
+	return dataURL(mime, b), nil
+}
+
+func writeCache(file string, img image.Image) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return err
+	}
+	return writeCacheFile(file, buf.Bytes())
+}
+
+// writeCacheFile writes b to file atomically. Renders racing for the same
+// entry each write a temp file of their own, and either result is good.
+func writeCacheFile(file string, b []byte) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// SetAssetEnhance picks the enhancement preset of an asset. The empty
+// preset follows the project.
+func (a *App) SetAssetEnhance(projectId string, assetId string, preset EnhancePreset) error {
+	if projectId == "" || assetId == "" {
+		return fmt.Errorf("required project or asset ID not found")
+	}
+	if err := preset.validate(true); err != nil {
+		return err
+	}
+	base, err := a.workspaceDir()
+	if err != nil {
+		return err
+	}
+	proj, err := readProject(base, projectId)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(proj.Assets, func(as AssetMetadata) bool { return as.ID == assetId })
+	if i == -1 {
+		return fmt.Errorf("asset with ID %s not found in project", assetId)
+	}
+	proj.Assets[i].Enhance = preset
+	proj.Assets[i].UpdatedAt = timestamp()
+	return writeProject(base, proj)
+}
+
+// UpdateProjectEnhance sets the default preset of a project's assets and
+// whether it is applied once at upload, replacing the stored images, or
+// every time a report is rendered.
+func (a *App) UpdateProjectEnhance(projectId string, preset EnhancePreset, onUpload bool) error {
+	if projectId == "" {
+		return errors.New("invalid project ID")
+	}
+	if err := preset.validate(false); err != nil {
+		return err
+	}
+	base, err := a.workspaceDir()
+	if err != nil {
+		return err
+	}
+	proj, err := readProject(base, projectId)
+	if err != nil {
+		return err
+	}
+	proj.Enhance = preset
+	proj.EnhanceOnUpload = onUpload
+	return writeProject(base, proj)
+}
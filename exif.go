@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+)
+
+// A minimal EXIF reader: it walks the TIFF structure inside a JPEG APP1
+// segment and keeps only the tags Hyuga uses.
+
+const (
+	tagMake             = 0x010F
+	tagModel            = 0x0110
+	tagOrientation      = 0x0112
+	tagDateTime         = 0x0132
+	tagExifIFD          = 0x8769
+	tagGPSIFD           = 0x8825
+	tagDateTimeOriginal = 0x9003
+	tagOffsetOriginal   = 0x9011
+	tagGPSLatitudeRef   = 0x0001
+	tagGPSLatitude      = 0x0002
+	tagGPSLongitudeRef  = 0x0003
+	tagGPSLongitude     = 0x0004
+	tagGPSAltitudeRef   = 0x0005
+	tagGPSAltitude      = 0x0006
+)
+
+// exifLayout is how EXIF writes dates, with no zone.
+const exifLayout = "2006:01:02 15:04:05"
+
+type exifData struct {
+	Orientation int // 1-8 as defined by EXIF, 0 when absent
+	Make        string
+	Model       string
+	TakenAt     time.Time
+	GPS         *GPSPosition
+}
+
+type tiffEntry struct {
+	typ   uint16
+	count uint32
+	data  []byte // the value, already resolved when it lives at an offset
+}
+
+type tiffReader struct {
+	b  []byte
+	bo binary.ByteOrder
+}
+
+var tiffTypeSize = map[uint16]uint32{1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 7: 1, 9: 4, 10: 8}
+
+func newTiffReader(b []byte) (*tiffReader, uint32, error) {
+	if len(b) < 8 {
+		return nil, 0, errors.New("truncated TIFF header")
+	}
+	r := &tiffReader{b: b}
+	switch string(b[:2]) {
+	case "II":
+		r.bo = binary.LittleEndian
+	case "MM":
+		r.bo = binary.BigEndian
+	default:
+		return nil, 0, errors.New("invalid TIFF byte order")
+	}
+	if r.bo.Uint16(b[2:]) != 42 {
+		return nil, 0, errors.New("invalid TIFF magic")
+	}
+	return r, r.bo.Uint32(b[4:]), nil
+}
+
+// ifd reads the directory at off, keyed by tag.
+func (r *tiffReader) ifd(off uint32) (map[uint16]tiffEntry, error) {
+	if uint64(off)+2 > uint64(len(r.b)) {
+		return nil, errors.New("IFD offset out of range")
+	}
+	n := uint32(r.bo.Uint16(r.b[off:]))
+	if uint64(off)+2+uint64(n)*12 > uint64(len(r.b)) {
+		return nil, errors.New("truncated IFD")
+	}
+	res := make(map[uint16]tiffEntry, n)
+	for i := range n {
+		e := r.b[off+2+i*12:]
+		ent := tiffEntry{typ: r.bo.Uint16(e[2:]), count: r.bo.Uint32(e[4:])}
+		size, ok := tiffTypeSize[ent.typ]
+		if !ok || ent.count > uint32(len(r.b)) {
+			continue
+		}
+		total := uint64(size) * uint64(ent.count)
+		if total <= 4 {
+			ent.data = e[8 : 8+total]
+		} else {
+			vo := uint64(r.bo.Uint32(e[8:]))
+			if vo+total > uint64(len(r.b)) {
+				continue
+			}
+			ent.data = r.b[vo : vo+total]
+		}
+		res[r.bo.Uint16(e)] = ent
+	}
+	return res, nil
+}
+
+// uint returns the first value of a BYTE, SHORT or LONG entry.
+func (r *tiffReader) uint(e tiffEntry) (uint32, bool) {
+	switch {
+	case e.typ == 1 && len(e.data) >= 1:
+		return uint32(e.data[0]), true
+	case e.typ == 3 && len(e.data) >= 2:
+		return uint32(r.bo.Uint16(e.data)), true
+	case e.typ == 4 && len(e.data) >= 4:
+		return r.bo.Uint32(e.data), true
+	}
+	return 0, false
+}
+
+func (r *tiffReader) ascii(e tiffEntry) string {
+	if e.typ != 2 {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimRight(string(e.data), "\x00"))
+}
+
+// rationals returns the values of a RATIONAL entry.
+func (r *tiffReader) rationals(e tiffEntry) []float64 {
+	if e.typ != 5 {
+		return nil
+	}
+	res := make([]float64, 0, e.count)
+	for i := 0; i+8 <= len(e.data); i += 8 {
+		num, den := r.bo.Uint32(e.data[i:]), r.bo.Uint32(e.data[i+4:])
+		if den == 0 {
+			return nil
+		}
+		res = append(res, float64(num)/float64(den))
+	}
+	return res
+}
+
+// gps reads a GPS IFD, nil when it holds no usable position.
+func (r *tiffReader) gps(ifd map[uint16]tiffEntry) *GPSPosition {
+	// degrees, minutes, seconds
+	coord := func(tag, ref uint16, neg string) (float64, bool) {
+		v := r.rationals(ifd[tag])
+		if len(v) != 3 {
+			return 0, false
+		}
+		d := v[0] + v[1]/60 + v[2]/3600
+		if strings.EqualFold(r.ascii(ifd[ref]), neg) {
+			d = -d
+		}
+		return d, true
+	}
+	lat, ok := coord(tagGPSLatitude, tagGPSLatitudeRef, "S")
+	if !ok || lat < -90 || lat > 90 {
+		return nil
+	}
+	lon, ok := coord(tagGPSLongitude, tagGPSLongitudeRef, "W")
+	if !ok || lon < -180 || lon > 180 {
+		return nil
+	}
+	pos := &GPSPosition{Latitude: lat, Longitude: lon}
+	if v := r.rationals(ifd[tagGPSAltitude]); len(v) == 1 {
+		pos.Altitude = v[0]
+		// reference 1 means below sea level
+		if ref, ok := r.uint(ifd[tagGPSAltitudeRef]); ok && ref == 1 {
+			pos.Altitude = -pos.Altitude
+		}
+	}
+	return pos
+}
+
+// exifTime parses an EXIF date. Without an offset it is taken as local
+// time, which is what cameras record.
+func exifTime(date, offset string) time.Time {
+	if date == "" {
+		return time.Time{}
+	}
+	if offset != "" {
+		if t, err := time.Parse(exifLayout+"-07:00", date+offset); err == nil {
+			return t
+		}
+	}
+	t, err := time.ParseInLocation(exifLayout, date, time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// jpegExif returns the TIFF payload of the EXIF APP1 segment of a JPEG,
+// or nil when there is none.
+func jpegExif(b []byte) []byte {
+	if len(b) < 4 || b[0] != 0xFF || b[1] != 0xD8 {
+		return nil
+	}
+	for i := 2; i+4 <= len(b); {
+		if b[i] != 0xFF {
+			return nil
+		}
+		marker := b[i+1]
+		// start of scan or end of image: no metadata past this point
+		if marker == 0xDA || marker == 0xD9 {
+			return nil
+		}
+		n := int(binary.BigEndian.Uint16(b[i+2:]))
+		if n < 2 || i+2+n > len(b) {
+			return nil
+		}
+		seg := b[i+4 : i+2+n]
+		if marker == 0xE1 && bytes.HasPrefix(seg, []byte("Exif\x00\x00")) {
+			return seg[6:]
+		}
+		i += 2 + n
+	}
+	return nil
+}
+
+// readExif decodes the EXIF metadata of a JPEG. Other formats, and JPEGs
+// without EXIF, return a zero exifData.
+func readExif(b []byte) (exifData, error) {
+	var x exifData
+	payload := jpegExif(b)
+	if sniffImage(b) == "image/tiff" {
+		// a TIFF is its own EXIF payload
+		payload = b
+	}
+	if payload == nil {
+		return x, nil
+	}
+	r, off, err := newTiffReader(payload)
+	if err != nil {
+		return x, err
+	}
+	ifd0, err := r.ifd(off)
+	if err != nil {
+		return x, err
+	}
+	if v, ok := r.uint(ifd0[tagOrientation]); ok && v >= 1 && v <= 8 {
+		x.Orientation = int(v)
+	}
+	x.Make = r.ascii(ifd0[tagMake])
+	x.Model = r.ascii(ifd0[tagModel])
+	x.TakenAt = exifTime(r.ascii(ifd0[tagDateTime]), "")
+
+	// the capture date and the position live in their own directories,
+	// which are optional: a broken one only loses its fields
+	if off, ok := r.uint(ifd0[tagExifIFD]); ok {
+		if sub, err := r.ifd(off); err == nil {
+			if t := exifTime(r.ascii(sub[tagDateTimeOriginal]), r.ascii(sub[tagOffsetOriginal])); !t.IsZero() {
+				x.TakenAt = t
+			}
+		}
+	}
+	if off, ok := r.uint(ifd0[tagGPSIFD]); ok {
+		if sub, err := r.ifd(off); err == nil {
+			x.GPS = r.gps(sub)
+		}
+	}
+	return x, nil
+}
+
+// captureInfo keeps the metadata of x worth storing, nil when there is none.
+func captureInfo(x exifData) *CaptureInfo {
+	c := &CaptureInfo{GPS: x.GPS}
+	if !x.TakenAt.IsZero() {
+		c.TakenAt = x.TakenAt.Format(time.RFC3339)
+	}
+	// models often repeat the maker, as in Canon "Canon EOS R6"
+	c.Device = x.Model
+	if x.Make != "" && !strings.HasPrefix(strings.ToLower(x.Model), strings.ToLower(x.Make)) {
+		c.Device = strings.TrimSpace(concat(x.Make, " ", x.Model))
+	}
+	if c.TakenAt == "" && c.Device == "" && c.GPS == nil {
+		return nil
+	}
+	return c
+}
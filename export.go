@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// csvText keeps spreadsheets from running text that starts like a formula.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return concat("'", s)
+	}
+	return s
+}
+
+// assetRows lays out the metadata of every asset of proj as a table, one
+// row per asset after the header, custom fields last and headed by label
+// and key, as labels may repeat built-in columns. Values stay in their
+// stored form, dates as YYYY-MM-DD, so spreadsheets can sort them.
+func assetRows(proj *Project) [][]string {
+	header := []string{"id", "publication", "edition_date", "section", "page", "continued_on", "headline", "byline", "media_type", "url", "created_at"}
+	for _, f := range proj.Fields {
+		header = append(header, csvText(concat(f.Label, " (", f.Key, ")")))
+	}
+	rows := [][]string{header}
+	for _, as := range proj.Assets {
+		var continued []string
+		for _, c := range as.Continuations {
+			continued = append(continued, c.PageNumber)
+		}
+		row := []string{as.ID, as.Publication, as.EditionDate, as.Section, as.PageNumber, strings.Join(continued, ", "),
+			as.Headline, as.Byline, string(as.MediaType), as.URL, as.CreatedAt}
+		for i := range row {
+			row[i] = csvText(row[i])
+		}
+		for _, f := range proj.Fields {
+			var cell string
+			switch v := as.Fields[f.Key].(type) {
+			case bool:
+				cell = strconv.FormatBool(v)
+			case float64:
+				cell = strconv.FormatFloat(v, 'f', -1, 64)
+			case string:
+				cell = csvText(v)
+			}
+			row = append(row, cell)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// ExportProjectCSV writes the metadata of the assets of a project as CSV
+// to file, asking for a location when file is empty. It returns the
+// written path.
+func (a *App) ExportProjectCSV(projectId string, file string) (string, error) {
+	if projectId == "" {
+		return "", errors.New("invalid project ID")
+	}
+	base, err := a.workspaceDir()
+	if err != nil {
+		return "", err
+	}
+	proj, err := readProject(base, projectId)
+	if err != nil {
+		return "", err
+	}
+	if file == "" {
+		file, err = runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+			DefaultFilename: concat(proj.Name, ".csv"),
+			Title:           "Exportar datos",
+			Filters:         []runtime.FileFilter{{DisplayName: "CSV (*.csv)", Pattern: "*.csv"}},
+		})
+		if err != nil {
+			return "", err
+		}
+		if file == "" {
+			return "", errors.New("no file path provided")
+		}
+	}
+
+	var buf bytes.Buffer
+	// the byte order mark makes Excel read the file as UTF-8
+	buf.WriteString("\ufeff")
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(assetRows(proj)); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(file, buf.Bytes(), 0644); err != nil {
+		return "", err
+	}
+	return file, nil
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Every client tracks something of their own: sentiment, the brand
+// mentioned, a spokesperson. A project defines custom fields for them and
+// every asset stores its values by field key. Values are strings for text,
+// date (YYYY-MM-DD) and enum fields, numbers and booleans otherwise.
+
+type FieldType string
+
+const (
+	FIELD_TEXT    FieldType = "text"
+	FIELD_NUMBER  FieldType = "number"
+	FIELD_DATE    FieldType = "date"
+	FIELD_ENUM    FieldType = "enum"
+	FIELD_BOOLEAN FieldType = "boolean"
+)
+
+type CustomField struct {
+	Key      string    `json:"key"`
+	Label    string    `json:"label"`
+	Type     FieldType `json:"type"`
+	Required bool      `json:"required"`
+	// Options are the values an enum field takes.
+	Options []string `json:"options,omitempty"`
+	// InCaption adds the field to the caption of the cutout page.
+	InCaption bool `json:"inCaption"`
+}
+
+var fieldKeyRe = regexp.MustCompile(`^[a-z][a-z0-9_]{0,39}$`)
+
+const maxFieldText = 1000
+
+// cleanFieldSchema trims fields and checks them.
+func cleanFieldSchema(fields []CustomField) error {
+	seen := map[string]bool{}
+	for i := range fields {
+		f := &fields[i]
+		f.Key = strings.TrimSpace(f.Key)
+		f.Label = strings.TrimSpace(f.Label)
+		if !fieldKeyRe.MatchString(f.Key) {
+			return fmt.Errorf("field key %q must be lowercase letters, digits and underscores", f.Key)
+		}
+		if seen[f.Key] {
+			return fmt.Errorf("field key %q is used twice", f.Key)
+		}
+		seen[f.Key] = true
+		if f.Label == "" {
+			return fmt.Errorf("field %s needs a label", f.Key)
+		}
+		switch f.Type {
+		case FIELD_TEXT, FIELD_NUMBER, FIELD_DATE, FIELD_BOOLEAN:
+			f.Options = nil
+		case FIELD_ENUM:
+			for j := range f.Options {
+				f.Options[j] = strings.TrimSpace(f.Options[j])
+				if f.Options[j] == "" || slices.Contains(f.Options[:j], f.Options[j]) {
+					return fmt.Errorf("field %s has an empty or repeated option", f.Key)
+				}
+			}
+			if len(f.Options) == 0 {
+				return fmt.Errorf("field %s needs options", f.Key)
+			}
+		default:
+			return fmt.Errorf("field %s has unknown type %q", f.Key, f.Type)
+		}
+	}
+	return nil
+}
+
+// cleanFieldValues checks values against the schema of a project and
+// drops empty ones, so a missing and a blank value are the same.
+func cleanFieldValues(schema []CustomField, values map[string]any) (map[string]any, error) {
+	res := map[string]any{}
+	for k := range values {
+		if !slices.ContainsFunc(schema, func(f CustomField) bool { return f.Key == k }) {
+			return nil, fmt.Errorf("unknown field %q", k)
+		}
+	}
+	for _, f := range schema {
+		v, ok := values[f.Key]
+		if s, isString := v.(string); isString {
+			v = strings.TrimSpace(s)
+			ok = v != ""
+		}
+		if !ok || v == nil {
+			if f.Required {
+				return nil, fmt.Errorf("%s is required", f.Label)
+			}
+			continue
+		}
+		invalid := func(want string) error {
+			return fmt.Errorf("%s must be %s", f.Label, want)
+		}
+		switch f.Type {
+		case FIELD_TEXT:
+			s, isString := v.(string)
+			if !isString {
+				return nil, invalid("text")
+			}
+			if utf8.RuneCountInString(s) > maxFieldText {
+				return nil, invalid(fmt.Sprintf("at most %d characters", maxFieldText))
+			}
+		case FIELD_NUMBER:
+			if _, isNumber := v.(float64); !isNumber {
+				return nil, invalid("a number")
+			}
+		case FIELD_DATE:
+			s, isString := v.(string)
+			if _, err := time.Parse(time.DateOnly, s); !isString || err != nil {
+				return nil, invalid("a YYYY-MM-DD date")
+			}
+		case FIELD_ENUM:
+			if s, isString := v.(string); !isString || !slices.Contains(f.Options, s) {
+				return nil, invalid(concat("one of ", strings.Join(f.Options, ", ")))
+			}
+		case FIELD_BOOLEAN:
+			if _, isBool := v.(bool); !isBool {
+				return nil, invalid("true or false")
+			}
+		}
+		res[f.Key] = v
+	}
+	if len(res) == 0 {
+		return nil, nil
+	}
+	return res, nil
+}
+
+// formatField writes the value of f for people to read.
+func formatField(lang string, f CustomField, v any) string {
+	switch v := v.(type) {
+	case bool:
+		if v {
+			return tr(lang, "yes")
+		}
+		return tr(lang, "no")
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		if f.Type == FIELD_DATE {
+			return formatDate(lang, v)
+		}
+		return v
+	}
+	return fmt.Sprint(v)
+}
+
+// fieldCaption lists the values of the fields shown in captions.
+func fieldCaption(lang string, schema []CustomField, values map[string]any) []string {
+	var parts []string
+	for _, f := range schema {
+		if v, ok := values[f.Key]; ok && f.InCaption {
+			parts = append(parts, concat(f.Label, ": ", formatField(lang, f, v)))
+		}
+	}
+	return parts
+}
+
+// GetFieldSchema returns the custom fields of a project, for forms.
+func (a *App) GetFieldSchema(projectId string) ([]CustomField, error) {
+	if projectId == "" {
+		return nil, errors.New("invalid project ID")
+	}
+	base, err := a.workspaceDir()
+	if err != nil {
+		return nil, err
+	}
+	proj, err := readProject(base, projectId)
+	if err != nil {
+		return nil, err
+	}
+	return proj.Fields, nil
+}
+
+// UpdateFieldSchema replaces the custom fields of a project. Values of
+// removed fields are dropped from its assets; the rest are checked again
+// the next time each asset is saved.
+func (a *App) UpdateFieldSchema(projectId string, fields []CustomField) error {
+	if projectId == "" {
+		return errors.New("invalid project ID")
+	}
+	if fields == nil {
+		fields = []CustomField{}
+	}
+	if err := cleanFieldSchema(fields); err != nil {
+		return err
+	}
+	base, err := a.workspaceDir()
+	if err != nil {
+		return err
+	}
+	proj, err := readProject(base, projectId)
+	if err != nil {
+		return err
+	}
+	proj.Fields = fields
+	for i := range proj.Assets {
+		as := &proj.Assets[i]
+		for k := range as.Fields {
+			if !slices.ContainsFunc(fields, func(f CustomField) bool { return f.Key == k }) {
+				delete(as.Fields, k)
+				as.UpdatedAt = timestamp()
+			}
+		}
+		if len(as.Fields) == 0 {
+			as.Fields = nil
+		}
+	}
+	return writeProject(base, proj)
+}
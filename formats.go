@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"net/http"
+
+	_ "golang.org/x/image/bmp"
+	"golang.org/x/image/tiff"
+	_ "golang.org/x/image/webp"
+)
+
+// Scanners save TIFF and web captures come as WebP, BMP or GIF. Besides
+// JPEG and PNG, these decode anywhere an image does, and are converted to
+// JPEG or PNG when they enter the workspace, as gopdf and the webview take
+// nothing else. A multi-page TIFF yields one page, the first unless
+// ExtractImagePage picks another beforehand; a GIF yields its first frame.
+
+// convertedImageTypes are accepted but stored converted.
+var convertedImageTypes = []string{"image/tiff", "image/webp", "image/bmp", "image/gif"}
+
+// sniffImage returns the type of the image in b, which DetectContentType
+// does for everything but TIFF.
+func sniffImage(b []byte) string {
+	if len(b) >= 4 && (string(b[:4]) == "II*\x00" || string(b[:4]) == "MM\x00*") {
+		return "image/tiff"
+	}
+	return http.DetectContentType(b)
+}
+
+// tiffPages returns the offsets of the directories of a TIFF, one per page.
+func tiffPages(b []byte) ([]uint32, error) {
+	r, off, err := newTiffReader(b)
+	if err != nil {
+		return nil, err
+	}
+	var pages []uint32
+	seen := map[uint32]bool{}
+	for off != 0 {
+		// a loop in the chain would never end
+		if seen[off] {
+			return nil, errors.New("invalid TIFF page chain")
+		}
+		seen[off] = true
+		if uint64(off)+2 > uint64(len(b)) {
+			return nil, errors.New("TIFF page offset out of range")
+		}
+		next := uint64(off) + 2 + uint64(r.bo.Uint16(b[off:]))*12
+		if next+4 > uint64(len(b)) {
+			return nil, errors.New("truncated TIFF page")
+		}
+		pages = append(pages, off)
+		off = r.bo.Uint32(b[next:])
+	}
+	if len(pages) == 0 {
+		return nil, errors.New("TIFF has no pages")
+	}
+	return pages, nil
+}
+
+// tiffPage returns a copy of the TIFF b whose first page is page, counted
+// from 1. Decoders only read the first page, so the header is pointed at
+// the directory of the one wanted.
+func tiffPage(b []byte, page int) ([]byte, error) {
+	pages, err := tiffPages(b)
+	if err != nil {
+		return nil, err
+	}
+	if page < 1 || page > len(pages) {
+		return nil, fmt.Errorf("page %d out of range, the TIFF has %d", page, len(pages))
+	}
+	out := bytes.Clone(b)
+	bo := binary.ByteOrder(binary.LittleEndian)
+	if out[0] == 'M' {
+		bo = binary.BigEndian
+	}
+	bo.PutUint32(out[4:], pages[page-1])
+	return out, nil
+}
+
+// ImagePageCount returns how many pages the image ref has: more than one
+// only for multi-page TIFFs.
+func (a *App) ImagePageCount(ref string) (int, error) {
+	b, err := imageBytes(ref)
+	if err != nil {
+		return 0, errors.New("not a valid base64 image")
+	}
+	if sniffImage(b) != "image/tiff" {
+		return 1, nil
+	}
+	pages, err := tiffPages(b)
+	if err != nil {
+		return 0, &ImageValidationError{Field: "tiff", Reason: err.Error()}
+	}
+	return len(pages), nil
+}
+
+// ExtractImagePage returns page of a multi-page TIFF, counted from 1, as a
+// data URL ready to upload.
+func (a *App) ExtractImagePage(ref string, page int) (string, error) {
+	b, err := imageBytes(ref)
+	if err != nil {
+		return "", errors.New("not a valid base64 image")
+	}
+	if sniffImage(b) != "image/tiff" {
+		if page != 1 {
+			return "", errors.New("only TIFF images have pages")
+		}
+		return ref, nil
+	}
+	if b, err = tiffPage(b, page); err != nil {
+		return "", &ImageValidationError{Field: "tiff", Reason: err.Error()}
+	}
+	// checked like any upload, so a bad page is reported here
+	if _, _, err := checkImage("tiff", b); err != nil {
+		return "", err
+	}
+	img, err := tiff.Decode(bytes.NewReader(b))
+	if err != nil {
+		return "", &ImageValidationError{Field: "tiff", Reason: concat("cannot decode page: ", err.Error())}
+	}
+	settings, err := currentSettings()
+	if err != nil {
+		return "", err
+	}
+	out, mime, err := encodeImage(img, settings.ImageQuality)
+	if err != nil {
+		return "", err
+	}
+	return dataURL(mime, out), nil
+}
+
+// convertImage re-encodes b as JPEG or PNG when it is in another format,
+// returning the file extension it should be saved with.
+func convertImage(b []byte, quality int) ([]byte, string, error) {
+	switch sniffImage(b) {
+	case "image/jpeg":
+		return b, ".jpg", nil
+	case "image/png":
+		return b, ".png", nil
+	}
+	img, _, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, "", err
+	}
+	out, mime, err := encodeImage(img, quality)
+	if err != nil {
+		return nil, "", err
+	}
+	if mime == "image/jpeg" {
+		return out, ".jpg", nil
+	}
+	return out, ".png", nil
+}
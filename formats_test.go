@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/tiff"
+)
+
+// The fixtures under testdata are small samples of every accepted format:
+// a 16x8 gradient as GIF, BMP and TIFF, a 1x1 WebP, and an 8x4 two-page
+// gray TIFF whose first page is black and second white. loop.tiff and
+// out-of-range.tiff break the page chain of the latter.
+
+func fixture(t *testing.T, name string) []byte {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestSniffImage(t *testing.T) {
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"gradient.gif", "image/gif"},
+		{"gradient.bmp", "image/bmp"},
+		{"gradient.tiff", "image/tiff"},
+		{"pages.tiff", "image/tiff"},
+		{"pixel.webp", "image/webp"},
+	}
+	for _, tt := range tests {
+		if got := sniffImage(fixture(t, tt.file)); got != tt.want {
+			t.Errorf("sniffImage(%s) = %s, want %s", tt.file, got, tt.want)
+		}
+	}
+	if got := sniffImage([]byte("II")); got == "image/tiff" {
+		t.Errorf("sniffImage of a 2-byte file = %s", got)
+	}
+}
+
+func TestTiffPages(t *testing.T) {
+	pages := fixture(t, "pages.tiff")
+	tests := []struct {
+		name  string
+		b     []byte
+		pages int
+		err   string
+	}{
+		{"single page", fixture(t, "gradient.tiff"), 1, ""},
+		{"two pages", pages, 2, ""},
+		{"loop", fixture(t, "loop.tiff"), 0, "invalid TIFF page chain"},
+		{"next page out of range", fixture(t, "out-of-range.tiff"), 0, "out of range"},
+		{"truncated page", pages[:len(pages)-20], 0, "truncated TIFF page"},
+		{"not a TIFF", fixture(t, "gradient.bmp"), 0, "TIFF"},
+	}
+	for _, tt := range tests {
+		got, err := tiffPages(tt.b)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: error %v, want %q", tt.name, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		} else if len(got) != tt.pages {
+			t.Errorf("%s: %d pages, want %d", tt.name, len(got), tt.pages)
+		}
+	}
+}
+
+func TestTiffPage(t *testing.T) {
+	b := fixture(t, "pages.tiff")
+	tests := []struct {
+		page int
+		gray uint32 // of the top left pixel, 8 bits
+		err  bool
+	}{
+		{page: 1, gray: 0},
+		{page: 2, gray: 255},
+		{page: 0, err: true},
+		{page: 3, err: true},
+	}
+	for _, tt := range tests {
+		out, err := tiffPage(b, tt.page)
+		if tt.err {
+			if err == nil {
+				t.Errorf("page %d: no error", tt.page)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("page %d: %v", tt.page, err)
+			continue
+		}
+		img, err := tiff.Decode(bytes.NewReader(out))
+		if err != nil {
+			t.Errorf("page %d: %v", tt.page, err)
+			continue
+		}
+		if r, _, _, _ := img.At(0, 0).RGBA(); r>>8 != tt.gray {
+			t.Errorf("page %d: gray %d, want %d", tt.page, r>>8, tt.gray)
+		}
+	}
+	if _, err := tiffPage(fixture(t, "loop.tiff"), 1); err == nil {
+		t.Error("page of a looping TIFF: no error")
+	}
+}
+
+func TestExtractImagePage(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	a := NewApp()
+	pages := base64.StdEncoding.EncodeToString(fixture(t, "pages.tiff"))
+	gif := base64.StdEncoding.EncodeToString(fixture(t, "gradient.gif"))
+	tests := []struct {
+		name string
+		ref  string
+		page int
+		gray uint32
+		err  bool
+	}{
+		{name: "first TIFF page", ref: pages, page: 1, gray: 0},
+		{name: "second TIFF page", ref: pages, page: 2, gray: 255},
+		{name: "missing TIFF page", ref: pages, page: 3, err: true},
+		{name: "looping TIFF", ref: base64.StdEncoding.EncodeToString(fixture(t, "loop.tiff")), page: 1, err: true},
+		{name: "GIF", ref: gif, page: 1},
+		{name: "second GIF page", ref: gif, page: 2, err: true},
+	}
+	for _, tt := range tests {
+		got, err := a.ExtractImagePage(tt.ref, tt.page)
+		if tt.err {
+			if err == nil {
+				t.Errorf("%s: no error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if tt.ref == gif {
+			if got != gif {
+				t.Errorf("%s: image changed", tt.name)
+			}
+			continue
+		}
+		img, err := decodeImage(got)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if img.Bounds().Dx() != 8 || img.Bounds().Dy() != 4 {
+			t.Errorf("%s: size %v", tt.name, img.Bounds())
+		}
+		if r, _, _, _ := img.At(0, 0).RGBA(); r>>8 != tt.gray {
+			t.Errorf("%s: gray %d, want %d", tt.name, r>>8, tt.gray)
+		}
+	}
+}
+
+// convertedFixtures are the fixtures stored converted, with their size.
+var convertedFixtures = []struct {
+	file string
+	w, h int
+}{
+	{"gradient.gif", 16, 8},
+	{"gradient.bmp", 16, 8},
+	{"gradient.tiff", 16, 8},
+	{"pages.tiff", 8, 4},
+	{"pixel.webp", 1, 1},
+}
+
+func TestConvertImage(t *testing.T) {
+	for _, tt := range convertedFixtures {
+		out, ext, err := convertImage(fixture(t, tt.file), 90)
+		if err != nil {
+			t.Errorf("%s: %v", tt.file, err)
+			continue
+		}
+		mime := sniffImage(out)
+		if !(mime == "image/jpeg" && ext == ".jpg" || mime == "image/png" && ext == ".png") {
+			t.Errorf("%s: converted to %s with extension %s", tt.file, mime, ext)
+		}
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(out))
+		if err != nil || cfg.Width != tt.w || cfg.Height != tt.h {
+			t.Errorf("%s: %dx%d, %v, want %dx%d", tt.file, cfg.Width, cfg.Height, err, tt.w, tt.h)
+		}
+		// JPEG and PNG are kept as they are
+		if again, againExt, err := convertImage(out, 90); err != nil || againExt != ext || !bytes.Equal(again, out) {
+			t.Errorf("%s: converted twice to %s, %v", tt.file, againExt, err)
+		}
+	}
+}
+
+func TestIngestImageConverts(t *testing.T) {
+	for _, tt := range convertedFixtures {
+		for _, maxSide := range []int{0, 4} {
+			img, err := ingestImage("sheet", fixture(t, tt.file), ingestOptions{MaxSide: maxSide, Quality: 90})
+			if err != nil {
+				t.Errorf("%s: %v", tt.file, err)
+				continue
+			}
+			if img.Mime != "image/jpeg" && img.Mime != "image/png" {
+				t.Errorf("%s: stored as %s", tt.file, img.Mime)
+			}
+			if sniffImage(img.Bytes) != img.Mime {
+				t.Errorf("%s: bytes are %s, not %s", tt.file, sniffImage(img.Bytes), img.Mime)
+			}
+			cfg, _, err := image.DecodeConfig(bytes.NewReader(img.Bytes))
+			if err != nil {
+				t.Errorf("%s: %v", tt.file, err)
+				continue
+			}
+			if side := max(cfg.Width, cfg.Height); maxSide > 0 && side > maxSide || maxSide == 0 && side != max(tt.w, tt.h) {
+				t.Errorf("%s with max side %d: %dx%d", tt.file, maxSide, cfg.Width, cfg.Height)
+			}
+		}
+	}
+}
@@ -2,7 +2,7 @@ import { useEffect, useState } from "react";
 import { useNavigate } from "react-router-dom";
 import {
   CreateProject,
-  LoadProjects,
+  LoadProjectSummaries,
   DeleteProject,
   UploadModels,
 } from "../../wailsjs/go/main/App.js";
@@ -25,7 +25,7 @@ import {
   FileInput,
 } from "@mantine/core";
 import Header from "../components/Header";
-import type { main } from "../../wailsjs/go/models";
+import { main } from "../../wailsjs/go/models";
 import "../App.css";
 import { useDisclosure, useMediaQuery } from "@mantine/hooks";
 import { useForm } from "@mantine/form";
@@ -38,23 +38,29 @@ type FormVals = {
 
 function Home() {
   const navigate = useNavigate();
-  const [projects, setProjects] = useState<main.Project[]>([]);
+  const [projects, setProjects] = useState<main.ProjectSummary[]>([]);
   const hh = 100;
   const fh = 80;
   const isMdUp = useMediaQuery("(min-width: 62em)"); // ~992px (Mantine md)
   const [opened, { open, close }] = useDisclosure(false);
 
+  const loadSummaries = async () => {
+    const page = await LoadProjectSummaries(
+      main.ProjectQuery.createFrom({ sortBy: "created_at", desc: true }),
+    );
+    return page.items;
+  };
+
   useEffect(() => {
     const init = async () => {
       try {
-        const list = await LoadProjects();
-        setProjects(list);
+        setProjects(await loadSummaries());
       } catch (err) {
-        console.error("LoadProjects failed", err);
+        console.error("LoadProjectSummaries failed", err);
       }
     };
     init();
-  }, [projects]);
+  }, []);
 
   const form = useForm<FormVals>({
     mode: "controlled",
@@ -78,9 +84,8 @@ function Home() {
 
   const handleCreateNewProject = async () => {
     try {
-      await CreateProject();
-      const refreshed = await LoadProjects();
-      setProjects(refreshed);
+      await CreateProject(main.CreateProjectOptions.createFrom({}));
+      setProjects(await loadSummaries());
     } catch (err) {
       console.error("Failed to create project", err);
     }
@@ -96,9 +101,8 @@ function Home() {
 
   const handleDeleteProject = async (id: string) => {
     try {
-      DeleteProject(id);
-      const refreshed = await LoadProjects();
-      setProjects(refreshed);
+      await DeleteProject(id);
+      setProjects(await loadSummaries());
     } catch (err) {
       console.error("Failed to open project", err);
     }
@@ -135,7 +139,7 @@ function Home() {
                     </Group>
 
                     <Text size="sm" c="dimmed" mb="md">
-                      {p.created_at}
+                      {new Date(p.created_at).toLocaleString()} · {p.asset_count} activos
                     </Text>
 
                     <Group gap="xs" wrap="wrap" justify="center" grow={!isMdUp}>
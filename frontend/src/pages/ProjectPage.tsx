@@ -3,7 +3,10 @@ import { useParams } from "react-router-dom";
 import type { main } from "../../wailsjs/go/models";
 import { useEffect, useRef, useState } from "react";
 import {
-  LoadProject,
+  GetProjectSummary,
+  LoadAssetSummaries,
+  GetThumbnail,
+  PreviewAssetImage,
   UploadAsset,
   DeleteAsset,
   GeneratePDF,
@@ -42,9 +45,31 @@ type FormVals = {
   model: string;
 };
 
+// Thumbs are the thumbnails of an asset, as data URLs.
+type Thumbs = {
+  sheet?: string;
+  cutout?: string;
+  model?: string;
+};
+
+// thumbnail fetches the thumbnail of an image hash or machote path. A
+// missing one only costs its picture.
+const thumbnail = async (ref: string, size: string) => {
+  if (!ref || ref === "empty") return undefined;
+  try {
+    return await GetThumbnail(ref, size);
+  } catch (err) {
+    console.error("GetThumbnail failed", err);
+    return undefined;
+  }
+};
+
 function ProjectPage() {
   const { projectId } = useParams<{ projectId: string }>();
-  const [project, setProject] = useState<main.Project>();
+  const [project, setProject] = useState<main.ProjectSummary>();
+  const [assets, setAssets] = useState<main.AssetSummary[]>([]);
+  const [thumbs, setThumbs] = useState<Record<string, Thumbs>>({});
+  const [preview, setPreview] = useState<string>();
   const [models, setModels] = useState<main.Model[]>([]);
   const [generatingPDF, setGeneratingPDF] = useState<boolean>(false);
   const [opened, { open, close }] = useDisclosure(false);
@@ -52,12 +77,31 @@ function ProjectPage() {
   const hh = 70;
   const fh = 80;
 
+  // loadAssets lists the assets of the project and fills in their
+  // thumbnails as they arrive. Full images are only loaded for previews.
+  const loadAssets = async (id: string) => {
+    const [p, list] = await Promise.all([
+      GetProjectSummary(id),
+      LoadAssetSummaries(id),
+    ]);
+    setProject(p);
+    setAssets(list);
+    list.forEach(async (as) => {
+      const [sheet, cutout, model] = await Promise.all([
+        thumbnail(as.sheetHash, "medium"),
+        thumbnail(as.cutoutHash, "medium"),
+        thumbnail(as.model, "small"),
+      ]);
+      setThumbs((prev) => ({
+        ...prev,
+        [as.id]: { sheet, cutout, model },
+      }));
+    });
+  };
+
   useEffect(() => {
     if (!projectId) return;
-    const l = async () => {
-      setProject(await LoadProject(projectId));
-    };
-    l();
+    loadAssets(projectId);
   }, [projectId]);
 
   useEffect(() => {
@@ -95,7 +139,7 @@ function ProjectPage() {
   const handleDelete = async (id: string) => {
     if (!project) return;
     await DeleteAsset(project?.id, id);
-    setProject(await LoadProject(project.id));
+    await loadAssets(project.id);
   };
 
   const handleUpload = async (vals: typeof form.values) => {
@@ -123,7 +167,7 @@ function ProjectPage() {
         model: vals.model,
       });
 
-      setProject(await LoadProject(project.id));
+      await loadAssets(project.id);
       form.reset();
       close();
     } catch (error) {
@@ -131,6 +175,15 @@ function ProjectPage() {
     }
   };
 
+  const handlePreview = async (id: string, photo: string) => {
+    if (!project) return;
+    try {
+      setPreview(await PreviewAssetImage(project.id, id, photo));
+    } catch (error) {
+      console.error(error);
+    }
+  };
+
   const handleProcessPDF = async () => {
     if (!project) return;
     try {
@@ -162,7 +215,7 @@ function ProjectPage() {
         >
           <LoadingOverlay visible={generatingPDF} />
           <Title order={2}>{project?.name}</Title>
-          {project?.assets.map((as) => (
+          {assets.map((as) => (
             <Container key={as.id} size="lg" px={{ base: "md", sm: "lg" }}>
               <Paper
                 my="lg"
@@ -199,32 +252,44 @@ function ProjectPage() {
                     </Group>
                   </Group>
                   <Image
-                    src={as.model}
-                    alt="Foto de hoja"
+                    src={thumbs[as.id]?.model}
+                    alt="Machote"
                     radius="md"
                     fit="contain"
-                    w="100%"
+                    h={120}
                   />
                   <SimpleGrid cols={{ base: 1, sm: 2 }} spacing="lg">
                     <Image
-                      src={as.sheet}
+                      src={thumbs[as.id]?.sheet}
                       alt="Foto de hoja"
                       radius="md"
                       fit="contain"
                       w="100%"
+                      style={{ cursor: "zoom-in" }}
+                      onClick={() => handlePreview(as.id, "sheet")}
                     />
                     <Image
-                      src={as.cutout}
+                      src={thumbs[as.id]?.cutout}
                       alt="Foto de nota"
                       radius="md"
                       fit="contain"
                       w="100%"
+                      style={{ cursor: "zoom-in" }}
+                      onClick={() => handlePreview(as.id, "cutout")}
                     />
                   </SimpleGrid>
                 </Stack>
               </Paper>
             </Container>
           ))}
+          <Modal
+            size="xl"
+            opened={!!preview}
+            onClose={() => setPreview(undefined)}
+            title="Vista previa"
+          >
+            <Image src={preview} alt="Vista previa" fit="contain" w="100%" />
+          </Modal>
           <Modal p="2em" opened={opened} onClose={close} title="Activo">
             <form onSubmit={form.onSubmit((v) => handleUpload(v))}>
               <FileInput
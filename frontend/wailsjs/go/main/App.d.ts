@@ -2,21 +2,123 @@
 // This file is automatically generated. DO NOT EDIT
 import {main} from '../models';
 
-export function CreateProject():Promise<void>;
+export function AddWorkspace(arg1:string,arg2:string):Promise<main.Workspace>;
+
+export function CheckWorkspace():Promise<Array<main.WorkspaceIssue>>;
+
+export function ChooseBackupDir():Promise<string>;
+
+export function CreateBackup():Promise<main.BackupInfo>;
+
+export function CreateProject(arg1:main.CreateProjectOptions):Promise<main.Project>;
 
 export function DeleteAsset(arg1:string,arg2:string):Promise<void>;
 
 export function DeleteProject(arg1:string):Promise<void>;
 
+export function DeletePublication(arg1:string):Promise<void>;
+
+export function DeleteTemplate(arg1:string):Promise<void>;
+
+export function DuplicateProject(arg1:string):Promise<main.Project>;
+
+export function EmptyTrash():Promise<void>;
+
+export function ExportProjectBundle(arg1:string,arg2:string):Promise<string>;
+
+export function ExportProjectCSV(arg1:string,arg2:string):Promise<string>;
+
+export function ExtractImagePage(arg1:string,arg2:number):Promise<string>;
+
 export function GeneratePDF(arg1:string):Promise<void>;
 
+export function GetFieldSchema(arg1:string):Promise<Array<main.CustomField>>;
+
+export function GetProjectSummary(arg1:string):Promise<main.ProjectSummary>;
+
+export function GetSettings():Promise<main.Settings>;
+
+export function GetThumbnail(arg1:string,arg2:main.ThumbnailSize):Promise<string>;
+
+export function ImagePageCount(arg1:string):Promise<number>;
+
+export function ImportProjectBundle(arg1:string):Promise<main.Project>;
+
+export function ListBackups():Promise<Array<main.BackupInfo>>;
+
+export function ListEnhancePresets():Promise<Array<main.EnhancePresetInfo>>;
+
+export function ListTrash():Promise<Array<main.TrashEntry>>;
+
+export function ListWorkspaces():Promise<Array<main.Workspace>>;
+
+export function LoadAssetSummaries(arg1:string):Promise<Array<main.AssetSummary>>;
+
 export function LoadAssets(arg1:string):Promise<Array<main.AssetMetadata>>;
 
 export function LoadModels():Promise<Array<main.Model>>;
 
 export function LoadProject(arg1:string):Promise<main.Project>;
 
-export function LoadProjects():Promise<Array<main.Project>>;
+export function LoadProjectSummaries(arg1:main.ProjectQuery):Promise<main.SummaryPage>;
+
+export function LoadProjects(arg1:main.ProjectQuery):Promise<Array<main.Project>>;
+
+export function LoadPublications():Promise<Array<main.Publication>>;
+
+export function LoadTemplates():Promise<Array<main.ProjectTemplate>>;
+
+export function MoveWorkspace(arg1:string,arg2:string):Promise<void>;
+
+export function PreviewAssetImage(arg1:string,arg2:string,arg3:main.PhotoType):Promise<string>;
+
+export function RemoveWorkspace(arg1:string):Promise<void>;
+
+export function RenameProject(arg1:string,arg2:string):Promise<void>;
+
+export function RepairWorkspace(arg1:Array<string>):Promise<Array<main.WorkspaceIssue>>;
+
+export function RestoreBackup(arg1:string):Promise<string>;
+
+export function RestoreFromTrash(arg1:string):Promise<void>;
+
+export function SaveProjectAsTemplate(arg1:string,arg2:string):Promise<main.ProjectTemplate>;
+
+export function SavePublication(arg1:main.Publication):Promise<main.Publication>;
+
+export function SaveTemplate(arg1:main.ProjectTemplate):Promise<main.ProjectTemplate>;
+
+export function Search(arg1:string,arg2:main.SearchFilters):Promise<Array<main.SearchResult>>;
+
+export function SetAnnotations(arg1:string,arg2:string,arg3:main.PhotoType,arg4:Array<main.Annotation>):Promise<void>;
+
+export function SetAssetEdits(arg1:string,arg2:string,arg3:main.PhotoType,arg4:Array<main.ImageEdit>):Promise<void>;
+
+export function SetAssetEnhance(arg1:string,arg2:string,arg3:main.EnhancePreset):Promise<void>;
+
+export function SetContinuations(arg1:string,arg2:string,arg3:Array<main.Continuation>):Promise<void>;
+
+export function SetCutoutRegions(arg1:string,arg2:string,arg3:Array<main.Region>,arg4:boolean):Promise<void>;
+
+export function SetHighlights(arg1:string,arg2:string,arg3:Array<main.Highlight>):Promise<void>;
+
+export function SetRedactedExport(arg1:string,arg2:boolean):Promise<void>;
+
+export function SetRedactions(arg1:string,arg2:string,arg3:main.PhotoType,arg4:Array<main.Redaction>):Promise<void>;
+
+export function SwitchWorkspace(arg1:string):Promise<void>;
+
+export function UpdateAsset(arg1:string,arg2:main.AssetMetadata):Promise<void>;
+
+export function UpdateFieldSchema(arg1:string,arg2:Array<main.CustomField>):Promise<void>;
+
+export function UpdateHighlightStyle(arg1:string,arg2:main.HighlightStyle):Promise<void>;
+
+export function UpdateProject(arg1:string,arg2:main.ProjectMetadata):Promise<void>;
+
+export function UpdateProjectEnhance(arg1:string,arg2:main.EnhancePreset,arg3:boolean):Promise<void>;
+
+export function UpdateSettings(arg1:main.Settings):Promise<main.Settings>;
 
 export function UploadAsset(arg1:string,arg2:main.AssetMetadata):Promise<void>;
 
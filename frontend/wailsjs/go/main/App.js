@@ -2,8 +2,24 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
-export function CreateProject() {
-  return window['go']['main']['App']['CreateProject']();
+export function AddWorkspace(arg1, arg2) {
+  return window['go']['main']['App']['AddWorkspace'](arg1, arg2);
+}
+
+export function CheckWorkspace() {
+  return window['go']['main']['App']['CheckWorkspace']();
+}
+
+export function ChooseBackupDir() {
+  return window['go']['main']['App']['ChooseBackupDir']();
+}
+
+export function CreateBackup() {
+  return window['go']['main']['App']['CreateBackup']();
+}
+
+export function CreateProject(arg1) {
+  return window['go']['main']['App']['CreateProject'](arg1);
 }
 
 export function DeleteAsset(arg1, arg2) {
@@ -14,10 +30,82 @@ export function DeleteProject(arg1) {
   return window['go']['main']['App']['DeleteProject'](arg1);
 }
 
+export function DeletePublication(arg1) {
+  return window['go']['main']['App']['DeletePublication'](arg1);
+}
+
+export function DeleteTemplate(arg1) {
+  return window['go']['main']['App']['DeleteTemplate'](arg1);
+}
+
+export function DuplicateProject(arg1) {
+  return window['go']['main']['App']['DuplicateProject'](arg1);
+}
+
+export function EmptyTrash() {
+  return window['go']['main']['App']['EmptyTrash']();
+}
+
+export function ExportProjectBundle(arg1, arg2) {
+  return window['go']['main']['App']['ExportProjectBundle'](arg1, arg2);
+}
+
+export function ExportProjectCSV(arg1, arg2) {
+  return window['go']['main']['App']['ExportProjectCSV'](arg1, arg2);
+}
+
+export function ExtractImagePage(arg1, arg2) {
+  return window['go']['main']['App']['ExtractImagePage'](arg1, arg2);
+}
+
 export function GeneratePDF(arg1) {
   return window['go']['main']['App']['GeneratePDF'](arg1);
 }
 
+export function GetFieldSchema(arg1) {
+  return window['go']['main']['App']['GetFieldSchema'](arg1);
+}
+
+export function GetProjectSummary(arg1) {
+  return window['go']['main']['App']['GetProjectSummary'](arg1);
+}
+
+export function GetSettings() {
+  return window['go']['main']['App']['GetSettings']();
+}
+
+export function GetThumbnail(arg1, arg2) {
+  return window['go']['main']['App']['GetThumbnail'](arg1, arg2);
+}
+
+export function ImagePageCount(arg1) {
+  return window['go']['main']['App']['ImagePageCount'](arg1);
+}
+
+export function ImportProjectBundle(arg1) {
+  return window['go']['main']['App']['ImportProjectBundle'](arg1);
+}
+
+export function ListBackups() {
+  return window['go']['main']['App']['ListBackups']();
+}
+
+export function ListEnhancePresets() {
+  return window['go']['main']['App']['ListEnhancePresets']();
+}
+
+export function ListTrash() {
+  return window['go']['main']['App']['ListTrash']();
+}
+
+export function ListWorkspaces() {
+  return window['go']['main']['App']['ListWorkspaces']();
+}
+
+export function LoadAssetSummaries(arg1) {
+  return window['go']['main']['App']['LoadAssetSummaries'](arg1);
+}
+
 export function LoadAssets(arg1) {
   return window['go']['main']['App']['LoadAssets'](arg1);
 }
@@ -30,8 +118,124 @@ export function LoadProject(arg1) {
   return window['go']['main']['App']['LoadProject'](arg1);
 }
 
-export function LoadProjects() {
-  return window['go']['main']['App']['LoadProjects']();
+export function LoadProjectSummaries(arg1) {
+  return window['go']['main']['App']['LoadProjectSummaries'](arg1);
+}
+
+export function LoadProjects(arg1) {
+  return window['go']['main']['App']['LoadProjects'](arg1);
+}
+
+export function LoadPublications() {
+  return window['go']['main']['App']['LoadPublications']();
+}
+
+export function LoadTemplates() {
+  return window['go']['main']['App']['LoadTemplates']();
+}
+
+export function MoveWorkspace(arg1, arg2) {
+  return window['go']['main']['App']['MoveWorkspace'](arg1, arg2);
+}
+
+export function PreviewAssetImage(arg1, arg2, arg3) {
+  return window['go']['main']['App']['PreviewAssetImage'](arg1, arg2, arg3);
+}
+
+export function RemoveWorkspace(arg1) {
+  return window['go']['main']['App']['RemoveWorkspace'](arg1);
+}
+
+export function RenameProject(arg1, arg2) {
+  return window['go']['main']['App']['RenameProject'](arg1, arg2);
+}
+
+export function RepairWorkspace(arg1) {
+  return window['go']['main']['App']['RepairWorkspace'](arg1);
+}
+
+export function RestoreBackup(arg1) {
+  return window['go']['main']['App']['RestoreBackup'](arg1);
+}
+
+export function RestoreFromTrash(arg1) {
+  return window['go']['main']['App']['RestoreFromTrash'](arg1);
+}
+
+export function SaveProjectAsTemplate(arg1, arg2) {
+  return window['go']['main']['App']['SaveProjectAsTemplate'](arg1, arg2);
+}
+
+export function SavePublication(arg1) {
+  return window['go']['main']['App']['SavePublication'](arg1);
+}
+
+export function SaveTemplate(arg1) {
+  return window['go']['main']['App']['SaveTemplate'](arg1);
+}
+
+export function Search(arg1, arg2) {
+  return window['go']['main']['App']['Search'](arg1, arg2);
+}
+
+export function SetAnnotations(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SetAnnotations'](arg1, arg2, arg3, arg4);
+}
+
+export function SetAssetEdits(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SetAssetEdits'](arg1, arg2, arg3, arg4);
+}
+
+export function SetAssetEnhance(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetAssetEnhance'](arg1, arg2, arg3);
+}
+
+export function SetContinuations(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetContinuations'](arg1, arg2, arg3);
+}
+
+export function SetCutoutRegions(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SetCutoutRegions'](arg1, arg2, arg3, arg4);
+}
+
+export function SetHighlights(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetHighlights'](arg1, arg2, arg3);
+}
+
+export function SetRedactedExport(arg1, arg2) {
+  return window['go']['main']['App']['SetRedactedExport'](arg1, arg2);
+}
+
+export function SetRedactions(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SetRedactions'](arg1, arg2, arg3, arg4);
+}
+
+export function SwitchWorkspace(arg1) {
+  return window['go']['main']['App']['SwitchWorkspace'](arg1);
+}
+
+export function UpdateAsset(arg1, arg2) {
+  return window['go']['main']['App']['UpdateAsset'](arg1, arg2);
+}
+
+export function UpdateFieldSchema(arg1, arg2) {
+  return window['go']['main']['App']['UpdateFieldSchema'](arg1, arg2);
+}
+
+export function UpdateHighlightStyle(arg1, arg2) {
+  return window['go']['main']['App']['UpdateHighlightStyle'](arg1, arg2);
+}
+
+export function UpdateProject(arg1, arg2) {
+  return window['go']['main']['App']['UpdateProject'](arg1, arg2);
+}
+
+export function UpdateProjectEnhance(arg1, arg2, arg3) {
+  return window['go']['main']['App']['UpdateProjectEnhance'](arg1, arg2, arg3);
+}
+
+export function UpdateSettings(arg1) {
+  return window['go']['main']['App']['UpdateSettings'](arg1);
 }
 
 export function UploadAsset(arg1, arg2) {
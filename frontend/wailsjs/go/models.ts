@@ -1,5 +1,271 @@
 export namespace main {
 	
+	export class Point {
+	    x: number;
+	    y: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Point(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.x = source["x"];
+	        this.y = source["y"];
+	    }
+	}
+	export class Annotation {
+	    kind: string;
+	    points: Point[];
+	    text?: string;
+	    color: string;
+	    width: number;
+	    opacity: number;
+	    fontSize?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Annotation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.points = this.convertValues(source["points"], Point);
+	        this.text = source["text"];
+	        this.color = source["color"];
+	        this.width = source["width"];
+	        this.opacity = source["opacity"];
+	        this.fontSize = source["fontSize"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GPSPosition {
+	    latitude: number;
+	    longitude: number;
+	    altitude: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new GPSPosition(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.latitude = source["latitude"];
+	        this.longitude = source["longitude"];
+	        this.altitude = source["altitude"];
+	    }
+	}
+	export class CaptureInfo {
+	    takenAt: string;
+	    device: string;
+	    gps?: GPSPosition;
+	
+	    static createFrom(source: any = {}) {
+	        return new CaptureInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.takenAt = source["takenAt"];
+	        this.device = source["device"];
+	        this.gps = this.convertValues(source["gps"], GPSPosition);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Continuation {
+	    sheet: string;
+	    pageNumber: string;
+	    cutout?: string;
+	    sheetEdits?: ImageEdit[];
+	    cutoutEdits?: ImageEdit[];
+	    sheetRedactions?: Redaction[];
+	    cutoutRedactions?: Redaction[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Continuation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sheet = source["sheet"];
+	        this.pageNumber = source["pageNumber"];
+	        this.cutout = source["cutout"];
+	        this.sheetEdits = this.convertValues(source["sheetEdits"], ImageEdit);
+	        this.cutoutEdits = this.convertValues(source["cutoutEdits"], ImageEdit);
+	        this.sheetRedactions = this.convertValues(source["sheetRedactions"], Redaction);
+	        this.cutoutRedactions = this.convertValues(source["cutoutRedactions"], Redaction);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Redaction {
+	    region: Region;
+	    mode: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Redaction(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.region = this.convertValues(source["region"], Region);
+	        this.mode = source["mode"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Highlight {
+	    rect?: Region;
+	    points?: Point[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Highlight(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.rect = this.convertValues(source["rect"], Region);
+	        this.points = this.convertValues(source["points"], Point);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Region {
+	    x: number;
+	    y: number;
+	    w: number;
+	    h: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Region(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.x = source["x"];
+	        this.y = source["y"];
+	        this.w = source["w"];
+	        this.h = source["h"];
+	    }
+	}
+	export class ImageEdit {
+	    op: string;
+	    angle?: number;
+	    crop?: Region;
+	    corners?: Point[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ImageEdit(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.op = source["op"];
+	        this.angle = source["angle"];
+	        this.crop = this.convertValues(source["crop"], Region);
+	        this.corners = this.convertValues(source["corners"], Point);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class AssetMetadata {
 	    id: string;
 	    sheet: string;
@@ -7,6 +273,29 @@ export namespace main {
 	    pageNumber: string;
 	    section: string;
 	    model: string;
+	    sheetEdits?: ImageEdit[];
+	    cutoutEdits?: ImageEdit[];
+	    cutoutRegions?: Region[];
+	    highlightCutout: boolean;
+	    highlights?: Highlight[];
+	    sheetAnnotations?: Annotation[];
+	    cutoutAnnotations?: Annotation[];
+	    sheetRedactions?: Redaction[];
+	    cutoutRedactions?: Redaction[];
+	    enhance?: string;
+	    baked?: string;
+	    continuations?: Continuation[];
+	    publicationId?: string;
+	    publication: string;
+	    editionDate: string;
+	    headline: string;
+	    byline: string;
+	    mediaType: string;
+	    url: string;
+	    fields?: Record<string, any>;
+	    capture?: CaptureInfo;
+	    createdAt: string;
+	    updatedAt: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new AssetMetadata(source);
@@ -20,8 +309,190 @@ export namespace main {
 	        this.pageNumber = source["pageNumber"];
 	        this.section = source["section"];
 	        this.model = source["model"];
+	        this.sheetEdits = this.convertValues(source["sheetEdits"], ImageEdit);
+	        this.cutoutEdits = this.convertValues(source["cutoutEdits"], ImageEdit);
+	        this.cutoutRegions = this.convertValues(source["cutoutRegions"], Region);
+	        this.highlightCutout = source["highlightCutout"];
+	        this.highlights = this.convertValues(source["highlights"], Highlight);
+	        this.sheetAnnotations = this.convertValues(source["sheetAnnotations"], Annotation);
+	        this.cutoutAnnotations = this.convertValues(source["cutoutAnnotations"], Annotation);
+	        this.sheetRedactions = this.convertValues(source["sheetRedactions"], Redaction);
+	        this.cutoutRedactions = this.convertValues(source["cutoutRedactions"], Redaction);
+	        this.enhance = source["enhance"];
+	        this.baked = source["baked"];
+	        this.continuations = this.convertValues(source["continuations"], Continuation);
+	        this.publicationId = source["publicationId"];
+	        this.publication = source["publication"];
+	        this.editionDate = source["editionDate"];
+	        this.headline = source["headline"];
+	        this.byline = source["byline"];
+	        this.mediaType = source["mediaType"];
+	        this.url = source["url"];
+	        this.fields = source["fields"];
+	        this.capture = this.convertValues(source["capture"], CaptureInfo);
+	        this.createdAt = source["createdAt"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AssetSummary {
+	    id: string;
+	    pageNumber: string;
+	    continuedOn?: string[];
+	    section: string;
+	    model: string;
+	    publication: string;
+	    headline: string;
+	    editionDate: string;
+	    sheetHash: string;
+	    cutoutHash: string;
+	    createdAt: string;
+	    updatedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AssetSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.pageNumber = source["pageNumber"];
+	        this.continuedOn = source["continuedOn"];
+	        this.section = source["section"];
+	        this.model = source["model"];
+	        this.publication = source["publication"];
+	        this.headline = source["headline"];
+	        this.editionDate = source["editionDate"];
+	        this.sheetHash = source["sheetHash"];
+	        this.cutoutHash = source["cutoutHash"];
+	        this.createdAt = source["createdAt"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	}
+	export class BackupInfo {
+	    name: string;
+	    path: string;
+	    createdAt: string;
+	    size: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BackupInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.createdAt = source["createdAt"];
+	        this.size = source["size"];
+	    }
+	}
+	export class BackupSettings {
+	    dir: string;
+	    intervalHours: number;
+	    keep: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BackupSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.dir = source["dir"];
+	        this.intervalHours = source["intervalHours"];
+	        this.keep = source["keep"];
+	    }
+	}
+	
+	
+	export class CreateProjectOptions {
+	    name: string;
+	    templateId: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CreateProjectOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.templateId = source["templateId"];
 	    }
 	}
+	export class CustomField {
+	    key: string;
+	    label: string;
+	    type: string;
+	    required: boolean;
+	    options?: string[];
+	    inCaption: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CustomField(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.label = source["label"];
+	        this.type = source["type"];
+	        this.required = source["required"];
+	        this.options = source["options"];
+	        this.inCaption = source["inCaption"];
+	    }
+	}
+	export class EnhancePresetInfo {
+	    preset: string;
+	    steps: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new EnhancePresetInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.preset = source["preset"];
+	        this.steps = source["steps"];
+	    }
+	}
+	
+	
+	export class HighlightStyle {
+	    mode: string;
+	    color: string;
+	    width: number;
+	    opacity: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new HighlightStyle(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mode = source["mode"];
+	        this.color = source["color"];
+	        this.width = source["width"];
+	        this.opacity = source["opacity"];
+	    }
+	}
+	
 	export class Model {
 	    label: string;
 	    value: string;
@@ -36,10 +507,39 @@ export namespace main {
 	        this.value = source["value"];
 	    }
 	}
+	
+	export class ProjectLayout {
+	    modelRatio: number;
+	    gap: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProjectLayout(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.modelRatio = source["modelRatio"];
+	        this.gap = source["gap"];
+	    }
+	}
 	export class Project {
 	    id: string;
 	    name: string;
 	    created_at: string;
+	    updated_at: string;
+	    client: string;
+	    description: string;
+	    period_start: string;
+	    period_end: string;
+	    status: string;
+	    tags: string[];
+	    layout: ProjectLayout;
+	    highlight_style: HighlightStyle;
+	    redacted_export: boolean;
+	    fields: CustomField[];
+	    enhance: string;
+	    enhance_on_upload: boolean;
+	    default_model: string;
 	    assets: AssetMetadata[];
 	
 	    static createFrom(source: any = {}) {
@@ -51,6 +551,20 @@ export namespace main {
 	        this.id = source["id"];
 	        this.name = source["name"];
 	        this.created_at = source["created_at"];
+	        this.updated_at = source["updated_at"];
+	        this.client = source["client"];
+	        this.description = source["description"];
+	        this.period_start = source["period_start"];
+	        this.period_end = source["period_end"];
+	        this.status = source["status"];
+	        this.tags = source["tags"];
+	        this.layout = this.convertValues(source["layout"], ProjectLayout);
+	        this.highlight_style = this.convertValues(source["highlight_style"], HighlightStyle);
+	        this.redacted_export = source["redacted_export"];
+	        this.fields = this.convertValues(source["fields"], CustomField);
+	        this.enhance = source["enhance"];
+	        this.enhance_on_upload = source["enhance_on_upload"];
+	        this.default_model = source["default_model"];
 	        this.assets = this.convertValues(source["assets"], AssetMetadata);
 	    }
 	
@@ -72,6 +586,407 @@ export namespace main {
 		    return a;
 		}
 	}
+	
+	export class ProjectMetadata {
+	    client: string;
+	    description: string;
+	    period_start: string;
+	    period_end: string;
+	    status: string;
+	    tags: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ProjectMetadata(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.client = source["client"];
+	        this.description = source["description"];
+	        this.period_start = source["period_start"];
+	        this.period_end = source["period_end"];
+	        this.status = source["status"];
+	        this.tags = source["tags"];
+	    }
+	}
+	export class ProjectQuery {
+	    client: string;
+	    status: string;
+	    tag: string;
+	    from: string;
+	    to: string;
+	    sortBy: string;
+	    desc: boolean;
+	    offset: number;
+	    limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProjectQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.client = source["client"];
+	        this.status = source["status"];
+	        this.tag = source["tag"];
+	        this.from = source["from"];
+	        this.to = source["to"];
+	        this.sortBy = source["sortBy"];
+	        this.desc = source["desc"];
+	        this.offset = source["offset"];
+	        this.limit = source["limit"];
+	    }
+	}
+	export class ProjectSummary {
+	    id: string;
+	    name: string;
+	    created_at: string;
+	    updated_at: string;
+	    client: string;
+	    status: string;
+	    tags: string[];
+	    period_start: string;
+	    period_end: string;
+	    asset_count: number;
+	    thumbnail_hash: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProjectSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.created_at = source["created_at"];
+	        this.updated_at = source["updated_at"];
+	        this.client = source["client"];
+	        this.status = source["status"];
+	        this.tags = source["tags"];
+	        this.period_start = source["period_start"];
+	        this.period_end = source["period_end"];
+	        this.asset_count = source["asset_count"];
+	        this.thumbnail_hash = source["thumbnail_hash"];
+	    }
+	}
+	export class ProjectTemplate {
+	    id: string;
+	    name: string;
+	    metadata: ProjectMetadata;
+	    layout: ProjectLayout;
+	    defaultModel: string;
+	    fields: CustomField[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ProjectTemplate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.metadata = this.convertValues(source["metadata"], ProjectMetadata);
+	        this.layout = this.convertValues(source["layout"], ProjectLayout);
+	        this.defaultModel = source["defaultModel"];
+	        this.fields = this.convertValues(source["fields"], CustomField);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SectionRate {
+	    section: string;
+	    unit: string;
+	    price: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SectionRate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.section = source["section"];
+	        this.unit = source["unit"];
+	        this.price = source["price"];
+	    }
+	}
+	export class Publication {
+	    id: string;
+	    name: string;
+	    defaultModel: string;
+	    sections: string[];
+	    pageWidthCm: number;
+	    pageHeightCm: number;
+	    rates: SectionRate[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Publication(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.defaultModel = source["defaultModel"];
+	        this.sections = source["sections"];
+	        this.pageWidthCm = source["pageWidthCm"];
+	        this.pageHeightCm = source["pageHeightCm"];
+	        this.rates = this.convertValues(source["rates"], SectionRate);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class SearchFilters {
+	    projectId: string;
+	    client: string;
+	    status: string;
+	    section: string;
+	    publication: string;
+	    mediaType: string;
+	    from: string;
+	    to: string;
+	    limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchFilters(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.projectId = source["projectId"];
+	        this.client = source["client"];
+	        this.status = source["status"];
+	        this.section = source["section"];
+	        this.publication = source["publication"];
+	        this.mediaType = source["mediaType"];
+	        this.from = source["from"];
+	        this.to = source["to"];
+	        this.limit = source["limit"];
+	    }
+	}
+	export class SearchResult {
+	    projectId: string;
+	    projectName: string;
+	    client: string;
+	    status: string;
+	    assetId: string;
+	    section: string;
+	    pageNumber: string;
+	    publication: string;
+	    headline: string;
+	    mediaType: string;
+	    editionDate: string;
+	    createdAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.projectId = source["projectId"];
+	        this.projectName = source["projectName"];
+	        this.client = source["client"];
+	        this.status = source["status"];
+	        this.assetId = source["assetId"];
+	        this.section = source["section"];
+	        this.pageNumber = source["pageNumber"];
+	        this.publication = source["publication"];
+	        this.headline = source["headline"];
+	        this.mediaType = source["mediaType"];
+	        this.editionDate = source["editionDate"];
+	        this.createdAt = source["createdAt"];
+	    }
+	}
+	
+	export class Workspace {
+	    id: string;
+	    name: string;
+	    path: string;
+	    active: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Workspace(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.active = source["active"];
+	    }
+	}
+	export class Settings {
+	    version: number;
+	    exportDir: string;
+	    pageSize: string;
+	    margin: number;
+	    language: string;
+	    imageQuality: number;
+	    maxImageSide: number;
+	    lastModel: string;
+	    windowWidth: number;
+	    windowHeight: number;
+	    trashRetentionDays: number;
+	    backup: BackupSettings;
+	    activeWorkspace: string;
+	    workspaces: Workspace[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Settings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.version = source["version"];
+	        this.exportDir = source["exportDir"];
+	        this.pageSize = source["pageSize"];
+	        this.margin = source["margin"];
+	        this.language = source["language"];
+	        this.imageQuality = source["imageQuality"];
+	        this.maxImageSide = source["maxImageSide"];
+	        this.lastModel = source["lastModel"];
+	        this.windowWidth = source["windowWidth"];
+	        this.windowHeight = source["windowHeight"];
+	        this.trashRetentionDays = source["trashRetentionDays"];
+	        this.backup = this.convertValues(source["backup"], BackupSettings);
+	        this.activeWorkspace = source["activeWorkspace"];
+	        this.workspaces = this.convertValues(source["workspaces"], Workspace);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SummaryPage {
+	    items: ProjectSummary[];
+	    total: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SummaryPage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.items = this.convertValues(source["items"], ProjectSummary);
+	        this.total = source["total"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TrashEntry {
+	    id: string;
+	    kind: string;
+	    projectId: string;
+	    projectName: string;
+	    assetId: string;
+	    deletedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TrashEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.kind = source["kind"];
+	        this.projectId = source["projectId"];
+	        this.projectName = source["projectName"];
+	        this.assetId = source["assetId"];
+	        this.deletedAt = source["deletedAt"];
+	    }
+	}
+	
+	export class WorkspaceIssue {
+	    id: string;
+	    severity: string;
+	    message: string;
+	    projectId: string;
+	    assetId: string;
+	    path: string;
+	    fix: string;
+	    fixLabel: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkspaceIssue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.severity = source["severity"];
+	        this.message = source["message"];
+	        this.projectId = source["projectId"];
+	        this.assetId = source["assetId"];
+	        this.path = source["path"];
+	        this.fix = source["fix"];
+	        this.fixLabel = source["fixLabel"];
+	    }
+	}
 
 }
 
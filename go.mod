@@ -7,6 +7,8 @@ require (
 	github.com/kpechenenko/rword v0.0.4
 	github.com/signintech/gopdf v0.33.0
 	github.com/wailsapp/wails/v2 v2.10.2
+	golang.org/x/image v0.24.0
+	golang.org/x/text v0.22.0
 )
 
 require (
@@ -36,7 +38,6 @@ require (
 	golang.org/x/crypto v0.33.0 // indirect
 	golang.org/x/net v0.35.0 // indirect
 	golang.org/x/sys v0.30.0 // indirect
-	golang.org/x/text v0.22.0 // indirect
 )
 
 // replace github.com/wailsapp/wails/v2 v2.10.2 => /Users/hnucamendi/go/pkg/mod
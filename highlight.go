@@ -0,0 +1,192 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/signintech/gopdf"
+)
+
+// Highlights mark where the clipping sat on the sheet page. Like cutout
+// regions they are normalized to the sheet after its edits.
+
+type HighlightMode string
+
+const (
+	HIGHLIGHT_OUTLINE HighlightMode = "outline"
+	HIGHLIGHT_FILL    HighlightMode = "fill"
+)
+
+// Highlight is either a rectangle or a polygon of at least three points.
+type Highlight struct {
+	Rect   *Region `json:"rect,omitempty"`
+	Points []Point `json:"points,omitempty"`
+}
+
+// HighlightStyle is set per project and applies to every sheet page.
+type HighlightStyle struct {
+	Mode    HighlightMode `json:"mode"`
+	Color   string        `json:"color"`   // #RRGGBB
+	Width   float64       `json:"width"`   // outline width, in points
+	Opacity float64       `json:"opacity"` // 0-1
+}
+
+func defaultHighlightStyle() HighlightStyle {
+	return HighlightStyle{Mode: HIGHLIGHT_OUTLINE, Color: "#DC2626", Width: 2, Opacity: 1}
+}
+
+func (s HighlightStyle) validate() error {
+	if s.Mode != HIGHLIGHT_OUTLINE && s.Mode != HIGHLIGHT_FILL {
+		return fmt.Errorf("unknown highlight mode %q", s.Mode)
+	}
+	if _, _, _, err := parseColor(s.Color); err != nil {
+		return err
+	}
+	if s.Mode == HIGHLIGHT_OUTLINE && (s.Width <= 0 || s.Width > 20) {
+		return errors.New("outline width must be between 0 and 20 points")
+	}
+	if s.Opacity <= 0 || s.Opacity > 1 {
+		return errors.New("opacity must be between 0 and 1")
+	}
+	return nil
+}
+
+// parseColor reads a #RRGGBB color.
+func parseColor(c string) (r, g, b uint8, err error) {
+	hex, ok := strings.CutPrefix(c, "#")
+	if !ok || len(hex) != 6 {
+		return 0, 0, 0, fmt.Errorf("invalid color %q, expected #RRGGBB", c)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid color %q, expected #RRGGBB", c)
+	}
+	return uint8(v >> 16), uint8(v >> 8), uint8(v), nil
+}
+
+func (h Highlight) validate() error {
+	inside := func(p Point) bool { return p.X >= 0 && p.X <= 1 && p.Y >= 0 && p.Y <= 1 }
+	switch {
+	case h.Rect != nil && h.Points == nil:
+		if !h.Rect.normalized() {
+			return errors.New("highlight rectangle must be non-empty and inside the sheet")
+		}
+	case h.Rect == nil && len(h.Points) >= 3:
+		if slices.ContainsFunc(h.Points, func(p Point) bool { return !inside(p) }) {
+			return errors.New("highlight points must be inside the sheet")
+		}
+	default:
+		return errors.New("a highlight is a rectangle or a polygon of at least three points")
+	}
+	return nil
+}
+
+// polygon returns the corners of h, normalized.
+func (h Highlight) polygon() []Point {
+	if r := h.Rect; r != nil {
+		return []Point{{r.X, r.Y}, {r.X + r.W, r.Y}, {r.X + r.W, r.Y + r.H}, {r.X, r.Y + r.H}}
+	}
+	return h.Points
+}
+
+// sheetHighlights returns what the sheet page of as highlights: its own
+// highlights plus, when asked for, its cutout regions.
+func sheetHighlights(as AssetMetadata) []Highlight {
+	hs := slices.Clone(as.Highlights)
+	if as.HighlightCutout {
+		for _, r := range as.CutoutRegions {
+			hs = append(hs, Highlight{Rect: &r})
+		}
+	}
+	return hs
+}
+
+// drawHighlights draws highlights over an image placed at x, y with size
+// rect.
+func drawHighlights(pdf *gopdf.GoPdf, hs []Highlight, style HighlightStyle, x, y float64, rect *gopdf.Rect) error {
+	if len(hs) == 0 {
+		return nil
+	}
+	r, g, b, err := parseColor(style.Color)
+	if err != nil {
+		return err
+	}
+	paint := "D"
+	if style.Mode == HIGHLIGHT_FILL {
+		paint = "F"
+		pdf.SetFillColor(r, g, b)
+	} else {
+		pdf.SetStrokeColor(r, g, b)
+		pdf.SetLineWidth(style.Width)
+	}
+	if style.Opacity < 1 {
+		if err := pdf.SetTransparency(gopdf.Transparency{Alpha: style.Opacity, BlendModeType: gopdf.NormalBlendMode}); err != nil {
+			return err
+		}
+		defer pdf.ClearTransparency()
+	}
+	for _, h := range hs {
+		var pts []gopdf.Point
+		for _, p := range h.polygon() {
+			pts = append(pts, gopdf.Point{X: x + p.X*rect.W, Y: y + p.Y*rect.H})
+		}
+		pdf.Polygon(pts, paint)
+	}
+	pdf.SetStrokeColor(0, 0, 0)
+	pdf.SetFillColor(0, 0, 0)
+	pdf.SetLineWidth(1)
+	return nil
+}
+
+// SetHighlights replaces the highlights drawn on the sheet page of an asset.
+func (a *App) SetHighlights(projectId string, assetId string, highlights []Highlight) error {
+	if projectId == "" || assetId == "" {
+		return fmt.Errorf("required project or asset ID not found")
+	}
+	for i, h := range highlights {
+		if err := h.validate(); err != nil {
+			return fmt.Errorf("highlight %d: %w", i+1, err)
+		}
+	}
+
+	base, err := a.workspaceDir()
+	if err != nil {
+		return err
+	}
+	proj, err := readProject(base, projectId)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(proj.Assets, func(as AssetMetadata) bool { return as.ID == assetId })
+	if i == -1 {
+		return fmt.Errorf("asset with ID %s not found in project", assetId)
+	}
+	proj.Assets[i].Highlights = highlights
+	proj.Assets[i].UpdatedAt = timestamp()
+	return writeProject(base, proj)
+}
+
+// UpdateHighlightStyle sets how the highlights of every asset of a project
+// are drawn.
+func (a *App) UpdateHighlightStyle(projectId string, style HighlightStyle) error {
+	if projectId == "" {
+		return errors.New("invalid project ID")
+	}
+	style.Color = strings.ToUpper(strings.TrimSpace(style.Color))
+	if err := style.validate(); err != nil {
+		return err
+	}
+	base, err := a.workspaceDir()
+	if err != nil {
+		return err
+	}
+	proj, err := readProject(base, projectId)
+	if err != nil {
+		return err
+	}
+	proj.HighlightStyle = style
+	return writeProject(base, proj)
+}
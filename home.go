@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -15,11 +16,46 @@ import (
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
+type ProjectStatus string
+
+const (
+	DRAFT     ProjectStatus = "draft"
+	IN_REVIEW ProjectStatus = "in_review"
+	DELIVERED ProjectStatus = "delivered"
+	ARCHIVED  ProjectStatus = "archived"
+)
+
+var statuses = []ProjectStatus{DRAFT, IN_REVIEW, DELIVERED, ARCHIVED}
+
 type Project struct {
-	Id        string          `json:"id"`
-	Name      string          `json:"name"`
-	CreatedAt string          `json:"created_at"`
-	Assets    []AssetMetadata `json:"assets"`
+	Id              string          `json:"id"`
+	Name            string          `json:"name"`
+	CreatedAt       string          `json:"created_at"`
+	UpdatedAt       string          `json:"updated_at"`
+	Client          string          `json:"client"`
+	Description     string          `json:"description"`
+	PeriodStart     string          `json:"period_start"`
+	PeriodEnd       string          `json:"period_end"`
+	Status          ProjectStatus   `json:"status"`
+	Tags            []string        `json:"tags"`
+	Layout          ProjectLayout   `json:"layout"`
+	HighlightStyle  HighlightStyle  `json:"highlight_style"`
+	RedactedExport  bool            `json:"redacted_export"`
+	Fields          []CustomField   `json:"fields"`
+	Enhance         EnhancePreset   `json:"enhance"`
+	EnhanceOnUpload bool            `json:"enhance_on_upload"`
+	DefaultModel    string          `json:"default_model"`
+	Assets          []AssetMetadata `json:"assets"`
+}
+
+// ProjectMetadata holds the user editable fields of a project.
+type ProjectMetadata struct {
+	Client      string        `json:"client"`
+	Description string        `json:"description"`
+	PeriodStart string        `json:"period_start"`
+	PeriodEnd   string        `json:"period_end"`
+	Status      ProjectStatus `json:"status"`
+	Tags        []string      `json:"tags"`
 }
 
 type Model struct {
@@ -40,36 +76,25 @@ func cleanupDirs(d []os.DirEntry) []os.DirEntry {
 	}
 	return cd
 }
-func (a *App) LoadProjects() ([]Project, error) {
-	base, err := getBaseConfigPath()
-	if err != nil {
-		return nil, err
-	}
-	projectsPath := filepath.Join(base, "projects")
-	err = os.MkdirAll(projectsPath, 0755)
+
+// LoadProjects returns the full projects matching q. Prefer
+// LoadProjectSummaries for listings, it never touches project.json.
+func (a *App) LoadProjects(q ProjectQuery) ([]Project, error) {
+	page, err := a.LoadProjectSummaries(q)
 	if err != nil {
 		return nil, err
 	}
-	dirs, err := os.ReadDir(projectsPath)
+	base, err := a.workspaceDir()
 	if err != nil {
 		return nil, err
 	}
-	cleanedDirs := cleanupDirs(dirs)
-	var res = make([]Project, len(cleanedDirs))
-	for i, v := range cleanedDirs {
-		if !v.IsDir() {
-			continue
-		}
-		cfgPath := filepath.Join(projectsPath, v.Name(), "project.json")
-		data, err := os.ReadFile(cfgPath)
+	var res = make([]Project, 0, len(page.Items))
+	for _, v := range page.Items {
+		p, err := readProject(base, v.Id)
 		if err != nil {
 			continue
 		}
-		var p Project
-		if err := json.Unmarshal(data, &p); err != nil {
-			continue
-		}
-		res[i] = p
+		res = append(res, *p)
 	}
 	return res, nil
 }
@@ -77,22 +102,123 @@ func (a *App) LoadProject(id string) (*Project, error) {
 	if id == "" {
 		return nil, errors.New("invalid project ID")
 	}
-	base, err := getBaseConfigPath()
+	base, err := a.workspaceDir()
 	if err != nil {
 		return nil, err
 	}
-	path := filepath.Join(base, "projects", concat("project-", id), "project.json")
-	b, err := os.ReadFile(path)
+	return readProject(base, id)
+}
+
+// UpdateProject replaces the editable metadata of a project.
+func (a *App) UpdateProject(id string, meta ProjectMetadata) error {
+	if id == "" {
+		return errors.New("invalid project ID")
+	}
+	if err := meta.validate(); err != nil {
+		return err
+	}
+	base, err := a.workspaceDir()
 	if err != nil {
-		return nil, err
+		return err
 	}
-	var p *Project
-	err = json.Unmarshal(b, &p)
+	p, err := readProject(base, id)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return p, nil
+	p.Client = strings.TrimSpace(meta.Client)
+	p.Description = strings.TrimSpace(meta.Description)
+	p.PeriodStart = meta.PeriodStart
+	p.PeriodEnd = meta.PeriodEnd
+	p.Status = meta.Status
+	p.Tags = cleanTags(meta.Tags)
+	return writeProject(base, p)
+}
+
+func (m ProjectMetadata) validate() error {
+	if !slices.Contains(statuses, m.Status) {
+		return fmt.Errorf("invalid project status %q", m.Status)
+	}
+	for _, d := range []string{m.PeriodStart, m.PeriodEnd} {
+		if d == "" {
+			continue
+		}
+		if _, err := time.Parse(time.DateOnly, d); err != nil {
+			return fmt.Errorf("invalid period date %q, expected YYYY-MM-DD", d)
+		}
+	}
+	if m.PeriodStart != "" && m.PeriodEnd != "" && m.PeriodEnd < m.PeriodStart {
+		return errors.New("period end is before period start")
+	}
+	return nil
+}
+
+func cleanTags(tags []string) []string {
+	res := []string{}
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		if t == "" || slices.ContainsFunc(res, func(v string) bool { return strings.EqualFold(v, t) }) {
+			continue
+		}
+		res = append(res, t)
+	}
+	return res
+}
+
+func projectDir(base, id string) string {
+	return filepath.Join(base, "projects", concat("project-", id))
+}
+
+func readProject(base, id string) (*Project, error) {
+	b, err := os.ReadFile(filepath.Join(projectDir(base, id), "project.json"))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read project folder: %w", err)
+	}
+	var p Project
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, fmt.Errorf("invalid project JSON %w", err)
+	}
+	// projects created before statuses existed are drafts
+	if p.Status == "" {
+		p.Status = DRAFT
+	}
+	if p.Tags == nil {
+		p.Tags = []string{}
+	}
+	if p.Fields == nil {
+		p.Fields = []CustomField{}
+	}
+	if p.Layout.ModelRatio <= 0 || p.Layout.ModelRatio >= 1 {
+		p.Layout = defaultLayout()
+	}
+	if p.HighlightStyle.validate() != nil {
+		p.HighlightStyle = defaultHighlightStyle()
+	}
+	if p.Enhance.validate(false) != nil {
+		p.Enhance = PRESET_NONE
+	}
+	migrateTimestamps(&p)
+	return &p, nil
 }
+
+// writeProject persists p and marks it as updated now.
+func writeProject(base string, p *Project) error {
+	p.UpdatedAt = timestamp()
+	return storeProject(base, p)
+}
+
+func storeProject(base string, p *Project) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize project: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(projectDir(base, p.Id), "project.json"), data, 0644); err != nil {
+		return fmt.Errorf("failed to write project file: %w", err)
+	}
+	return indexProject(base, p)
+}
+
+// getBaseConfigPath returns the app config folder. Workspace data is
+// resolved through App.workspaceDir.
 func getBaseConfigPath() (string, error) {
 	dir, err := os.UserConfigDir()
 	if err != nil {
@@ -110,53 +236,140 @@ func ensureUnique(p Project, a AssetMetadata) bool {
 	return true
 }
 
-func (a *App) CreateProject() error {
-	name, err := Generate()
+// CreateProjectOptions configures a new project. Name defaults to the
+// template name (when one is given) or a random "adjective-noun" name.
+type CreateProjectOptions struct {
+	Name       string `json:"name"`
+	TemplateId string `json:"templateId"`
+}
+
+func (a *App) CreateProject(opts CreateProjectOptions) (*Project, error) {
+	base, err := a.workspaceDir()
 	if err != nil {
-		fmt.Println(err)
-		return err
+		return nil, err
 	}
 
-	id := uuid.NewString()
-	base, err := getBaseConfigPath()
-	if err != nil {
-		return err
+	var tmpl *ProjectTemplate
+	if opts.TemplateId != "" {
+		tmpl, err = findTemplate(base, opts.TemplateId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+
+	name := strings.TrimSpace(opts.Name)
+	if name == "" && tmpl != nil {
+		name = concat(tmpl.Name, " ", now.Format(time.DateOnly))
 	}
-	projectsDir := filepath.Join(base, "projects", concat("project-", id))
+	if name == "" {
+		name, err = Generate()
+		if err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+	}
+
+	id := uuid.NewString()
+	projectsDir := projectDir(base, id)
 	if err := os.MkdirAll(projectsDir, 0755); err != nil {
+		return nil, err
+	}
+	proj := Project{
+		Id:             id,
+		Name:           name,
+		CreatedAt:      now.Format(time.RFC3339),
+		Status:         DRAFT,
+		Tags:           []string{},
+		Layout:         defaultLayout(),
+		HighlightStyle: defaultHighlightStyle(),
+		Enhance:        PRESET_NONE,
+		Fields:         []CustomField{},
+		Assets:         []AssetMetadata{},
+	}
+	if tmpl != nil {
+		proj.Client = tmpl.Metadata.Client
+		proj.Description = tmpl.Metadata.Description
+		proj.Status = tmpl.Metadata.Status
+		proj.Tags = cleanTags(tmpl.Metadata.Tags)
+		proj.Layout = tmpl.Layout
+		proj.DefaultModel = tmpl.DefaultModel
+		if tmpl.Fields != nil {
+			proj.Fields = tmpl.Fields
+		}
+	}
+	if err := writeProject(base, &proj); err != nil {
+		return nil, err
+	}
+	return &proj, nil
+}
+
+func (a *App) RenameProject(id string, name string) error {
+	name = strings.TrimSpace(name)
+	if id == "" || name == "" {
+		return errors.New("project ID and name are required")
+	}
+	base, err := a.workspaceDir()
+	if err != nil {
 		return err
 	}
-	loc, err := time.LoadLocation("Local")
+	p, err := readProject(base, id)
 	if err != nil {
 		return err
 	}
-	proj := Project{
-		Id:        id,
-		Name:      name,
-		CreatedAt: time.Now().Local().In(loc).Format(time.DateTime),
-		Assets:    []AssetMetadata{},
+	p.Name = name
+	return writeProject(base, p)
+}
+
+// DuplicateProject copies a project folder, assets and any stored blobs
+// included, under a new ID. The copy starts over as a draft.
+func (a *App) DuplicateProject(id string) (*Project, error) {
+	if id == "" {
+		return nil, errors.New("invalid project ID")
 	}
-	data, err := json.MarshalIndent(proj, "", "  ")
+	base, err := a.workspaceDir()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	err = os.WriteFile(filepath.Join(projectsDir, "project.json"), data, 0644)
+	p, err := readProject(base, id)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+
+	newId := uuid.NewString()
+	if err := copyDir(projectDir(base, id), projectDir(base, newId)); err != nil {
+		os.RemoveAll(projectDir(base, newId))
+		return nil, fmt.Errorf("failed to copy project: %w", err)
+	}
+
+	p.Id = newId
+	p.Name = concat(p.Name, " (copia)")
+	p.CreatedAt = timestamp()
+	p.Status = DRAFT
+	if err := writeProject(base, p); err != nil {
+		return nil, err
+	}
+	return p, nil
 }
+
+// DeleteProject moves the project folder to the trash, see RestoreFromTrash.
 func (a *App) DeleteProject(id string) error {
-	base, err := getBaseConfigPath()
+	if id == "" {
+		return errors.New("invalid project ID")
+	}
+	base, err := a.workspaceDir()
 	if err != nil {
 		return err
 	}
-	path := filepath.Join(base, "projects", concat("project-", id))
-	err = os.RemoveAll(path)
-	if err != nil {
+	name := id
+	if p, err := readProject(base, id); err == nil {
+		name = p.Name
+	}
+	if err := trashProject(base, id, name); err != nil {
 		return err
 	}
-	return nil
+	return unindexProject(base, id)
 }
 
 func hashFromSavedPath(p string) (string, bool) {
@@ -175,54 +388,45 @@ func hashFromSavedPath(p string) (string, bool) {
 	return noExt, true
 }
 
-func (a *App) UploadModels() error {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return err
-	}
-
-	baseDir, err := getBaseConfigPath()
-	if err != nil {
-		return err
-	}
+// modelLibrary is the machote catalog in models/models.json. Images are
+// stored once under models/images, named after the sha256 of their bytes.
+type modelLibrary struct {
+	file      string
+	imagesDir string
+	models    []Model
+	seenHash  map[string]string
+	seenPath  map[string]struct{}
+}
 
-	imagesDir := filepath.Join(baseDir, "models", "images")
+func loadModelLibrary(base string) (*modelLibrary, error) {
+	imagesDir := filepath.Join(base, "models", "images")
 	if err := os.MkdirAll(imagesDir, 0755); err != nil {
-		return err
-	}
-
-	cfg := runtime.OpenDialogOptions{
-		DefaultDirectory:           homeDir,
-		ShowHiddenFiles:            false,
-		CanCreateDirectories:       false,
-		TreatPackagesAsDirectories: false,
-		Filters: []runtime.FileFilter{
-			{
-				DisplayName: "Images (*.jpg;*.png)",
-				Pattern:     "*.png;*.jpg",
-			},
-		},
+		return nil, err
 	}
 
-	modelsFile := filepath.Join(baseDir, "models", "models.json")
+	modelsFile := filepath.Join(base, "models", "models.json")
 	mfb, err := os.ReadFile(modelsFile)
 	if err != nil {
 		if !errors.Is(err, os.ErrNotExist) {
-			return err
+			return nil, err
+		}
+		lang := "es"
+		if s, err := currentSettings(); err == nil {
+			lang = s.Language
 		}
-		v := []Model{{Label: "Seleciona Machote", Value: "empty"}}
+		v := []Model{{Label: tr(lang, "placeholder"), Value: "empty"}}
 		b, err := json.Marshal(v)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		tmp := modelsFile + ".tmp"
 		err = os.WriteFile(tmp, b, 0644)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		err = os.Rename(tmp, modelsFile)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		mfb = b
 	}
@@ -230,17 +434,90 @@ func (a *App) UploadModels() error {
 	var existingModels []Model
 	err = json.Unmarshal(mfb, &existingModels)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	seenHash := make(map[string]struct{}, len(existingModels))
-	seenPath := make(map[string]struct{}, len(existingModels))
+	l := &modelLibrary{
+		file:      modelsFile,
+		imagesDir: imagesDir,
+		models:    existingModels,
+		seenHash:  make(map[string]string, len(existingModels)),
+		seenPath:  make(map[string]struct{}, len(existingModels)),
+	}
 	for _, m := range existingModels {
 		if h, ok := hashFromSavedPath(m.Value); ok {
-			seenHash[h] = struct{}{}
+			l.seenHash[h] = m.Value
+		}
+		l.seenPath[m.Value] = struct{}{}
+	}
+	return l, nil
+}
+
+// add stores the image b under the label name unless an image with the
+// same hash is already listed, and returns the path it is listed under.
+func (l *modelLibrary) add(name string, b []byte) (string, error) {
+	sum := sha256.Sum256(b)
+	h := hex.EncodeToString(sum[:])
+	ext := strings.ToLower(filepath.Ext(name))
+	outPath := filepath.Join(l.imagesDir, h+ext)
+
+	if listed, dup := l.seenHash[h]; dup {
+		if _, alreadyListed := l.seenPath[listed]; alreadyListed {
+			return listed, nil
+		}
+	}
+
+	if _, err := os.Stat(outPath); errors.Is(err, os.ErrNotExist) {
+		if err := os.WriteFile(outPath, b, 0644); err != nil {
+			return "", fmt.Errorf("could not write file %s: %w", outPath, err)
 		}
-		seenPath[m.Value] = struct{}{}
+	}
 
+	l.models = append(l.models, Model{Label: name, Value: outPath})
+	l.seenHash[h] = outPath
+	l.seenPath[outPath] = struct{}{}
+	return outPath, nil
+}
+
+func (l *modelLibrary) save() error {
+	data, err := json.MarshalIndent(l.models, "", " ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(l.file, data, 0644)
+}
+
+func (a *App) UploadModels() error {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+
+	baseDir, err := a.workspaceDir()
+	if err != nil {
+		return err
+	}
+
+	lib, err := loadModelLibrary(baseDir)
+	if err != nil {
+		return err
+	}
+	settings, err := currentSettings()
+	if err != nil {
+		return err
+	}
+
+	cfg := runtime.OpenDialogOptions{
+		DefaultDirectory:           homeDir,
+		ShowHiddenFiles:            false,
+		CanCreateDirectories:       false,
+		TreatPackagesAsDirectories: false,
+		Filters: []runtime.FileFilter{
+			{
+				DisplayName: "Images (*.jpg;*.png;*.tif;*.webp;*.bmp;*.gif)",
+				Pattern:     "*.png;*.jpg;*.jpeg;*.tif;*.tiff;*.webp;*.bmp;*.gif",
+			},
+		},
 	}
 
 	models, err := runtime.OpenMultipleFilesDialog(a.ctx, cfg)
@@ -255,42 +532,24 @@ func (a *App) UploadModels() error {
 			fmt.Println(concat("could not read file: ", m))
 			continue
 		}
-		sum := sha256.Sum256(b)
-		h := hex.EncodeToString(sum[:])
-		ext := strings.ToLower(filepath.Ext(fn))
-		outName := h + ext
-		outPath := filepath.Join(imagesDir, outName)
-
-		if _, dup := seenHash[h]; dup {
-			if _, alreadyListed := seenPath[outPath]; alreadyListed {
-				continue
-			}
-			existingModels = append(existingModels, Model{Label: fn, Value: outPath})
-			seenPath[outPath] = struct{}{}
+		if _, _, err := checkImage(fn, b); err != nil {
+			fmt.Println(err)
 			continue
 		}
-
-		if _, err := os.Stat(outPath); errors.Is(err, os.ErrNotExist) {
-			if err := os.WriteFile(outPath, b, 0644); err != nil {
-				fmt.Println(concat("could not write file ", outPath, " err ", err.Error()))
-				continue
-			}
+		// machotes are embedded as they are, so they must be JPEG or PNG
+		b, ext, err := convertImage(b, settings.ImageQuality)
+		if err != nil {
+			fmt.Println(concat("could not convert ", fn, ": ", err.Error()))
+			continue
 		}
-
-		existingModels = append(existingModels, Model{Label: fn, Value: outPath})
-		seenHash[h] = struct{}{}
-		seenPath[outPath] = struct{}{}
-	}
-
-	data, err := json.MarshalIndent(existingModels, "", " ")
-	if err != nil {
-		return err
-	}
-
-	err = os.WriteFile(modelsFile, data, 0644)
-	if err != nil {
-		return err
+		fn = strings.TrimSuffix(fn, filepath.Ext(fn)) + ext
+		path, err := lib.add(fn, b)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		cacheThumbnails(baseDir, path)
 	}
 
-	return nil
+	return lib.save()
 }
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"golang.org/x/image/draw"
+)
+
+// Images are checked when they enter the workspace so a bad file is
+// rejected at upload, naming the field, instead of failing a whole report
+// in GeneratePDF.
+
+const (
+	maxImageBytes       = 40 << 20
+	maxImageSide        = 20000
+	maxImagePixels      = 150_000_000 // keeps a decoded image under ~600MB
+	defaultMaxImageSide = 4000
+)
+
+// acceptedImageTypes are stored as uploaded, see convertedImageTypes for
+// the rest.
+var acceptedImageTypes = []string{"image/jpeg", "image/png"}
+
+// ImageValidationError reports which image of an upload was rejected.
+type ImageValidationError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+func (e *ImageValidationError) Error() string {
+	return concat("invalid ", e.Field, " image: ", e.Reason)
+}
+
+type ingestOptions struct {
+	MaxSide int // 0 keeps the original size
+	Quality int
+}
+
+func ingestOptionsFrom(s *Settings) ingestOptions {
+	return ingestOptions{MaxSide: s.MaxImageSide, Quality: s.ImageQuality}
+}
+
+// checkImage sniffs and bounds-checks b without decoding the pixels.
+func checkImage(field string, b []byte) (image.Config, string, error) {
+	invalid := func(format string, args ...any) error {
+		return &ImageValidationError{Field: field, Reason: fmt.Sprintf(format, args...)}
+	}
+	if len(b) == 0 {
+		return image.Config{}, "", invalid("empty file")
+	}
+	if len(b) > maxImageBytes {
+		return image.Config{}, "", invalid("file is %d MB, the limit is %d MB", len(b)>>20, maxImageBytes>>20)
+	}
+	mime := sniffImage(b)
+	if !slices.Contains(acceptedImageTypes, mime) && !slices.Contains(convertedImageTypes, mime) {
+		return image.Config{}, "", invalid("unsupported type %s", mime)
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(b))
+	if err != nil {
+		return image.Config{}, "", invalid("cannot read image header: %v", err)
+	}
+	if cfg.Width < 1 || cfg.Height < 1 || cfg.Width > maxImageSide || cfg.Height > maxImageSide {
+		return image.Config{}, "", invalid("%dx%d pixels is outside the 1-%d range", cfg.Width, cfg.Height, maxImageSide)
+	}
+	if cfg.Width*cfg.Height > maxImagePixels {
+		return image.Config{}, "", invalid("%dx%d pixels is too large", cfg.Width, cfg.Height)
+	}
+	return cfg, mime, nil
+}
+
+// ingested is an image ready to store along with the EXIF metadata read
+// from the original, which re-encoding drops.
+type ingested struct {
+	Bytes []byte
+	Mime  string
+	Exif  exifData
+}
+
+// ingestImage validates b and returns it ready to store: a JPEG or PNG,
+// upright and no larger than opts.MaxSide. Images that need none of it
+// keep their bytes.
+func ingestImage(field string, b []byte, opts ingestOptions) (*ingested, error) {
+	cfg, mime, err := checkImage(field, b)
+	if err != nil {
+		return nil, err
+	}
+	res := &ingested{Bytes: b, Mime: mime}
+	res.Exif, err = readExif(b)
+	if err != nil {
+		// broken metadata is common in phone photos and not worth rejecting
+		fmt.Println(concat("ignoring unreadable EXIF in ", field, ": ", err.Error()))
+	}
+	upright := res.Exif.Orientation <= 1
+	small := opts.MaxSide <= 0 || max(cfg.Width, cfg.Height) <= opts.MaxSide
+	convert := slices.Contains(convertedImageTypes, mime)
+	if upright && small && !convert {
+		return res, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, &ImageValidationError{Field: field, Reason: concat("cannot decode image: ", err.Error())}
+	}
+	img = orient(img, res.Exif.Orientation)
+	if !small {
+		img = downscale(img, opts.MaxSide)
+	}
+	if res.Bytes, res.Mime, err = encodeImage(img, opts.Quality); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// encodeImage stores opaque images as JPEG and keeps PNG for the rest, so
+// machotes and cutouts with transparency keep their alpha.
+func encodeImage(img image.Image, quality int) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if o, ok := img.(interface{ Opaque() bool }); ok && o.Opaque() {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "image/jpeg", nil
+	}
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "image/png", nil
+}
+
+func dataURL(mime string, b []byte) string {
+	return concat("data:", mime, ";base64,", base64.StdEncoding.EncodeToString(b))
+}
+
+// ingestImageRef validates an uploaded base64 image and returns the
+// reference to store, which is ref itself when nothing had to change.
+func ingestImageRef(field, ref string, opts ingestOptions) (string, exifData, error) {
+	b, err := imageBytes(ref)
+	if err != nil {
+		return "", exifData{}, &ImageValidationError{Field: field, Reason: "not a valid base64 image"}
+	}
+	img, err := ingestImage(field, b, opts)
+	if err != nil {
+		return "", exifData{}, err
+	}
+	if bytes.Equal(img.Bytes, b) {
+		return ref, img.Exif, nil
+	}
+	return dataURL(img.Mime, img.Bytes), img.Exif, nil
+}
+
+// checkImageFile validates a machote saved in the workspace.
+func checkImageFile(field, path string) error {
+	if !filepath.IsAbs(path) {
+		return &ImageValidationError{Field: field, Reason: concat("unknown machote ", path)}
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return &ImageValidationError{Field: field, Reason: err.Error()}
+	}
+	_, _, err = checkImage(field, b)
+	return err
+}
+
+// orient turns an image stored with EXIF orientation o upright.
+func orient(img image.Image, o int) image.Image {
+	if o <= 1 || o > 8 {
+		return img
+	}
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	// orientations 5-8 swap the axes
+	dw, dh := w, h
+	if o >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := range h {
+		for x := range w {
+			var dx, dy int
+			switch o {
+			case 2: // flip horizontally
+				dx, dy = w-1-x, y
+			case 3: // rotate 180°
+				dx, dy = w-1-x, h-1-y
+			case 4: // flip vertically
+				dx, dy = x, h-1-y
+			case 5: // transpose
+				dx, dy = y, x
+			case 6: // rotate 90° clockwise
+				dx, dy = h-1-y, x
+			case 7: // transverse
+				dx, dy = h-1-y, w-1-x
+			case 8: // rotate 90° counterclockwise
+				dx, dy = y, w-1-x
+			}
+			dst.Set(dx, dy, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+// downscale shrinks img so its longest side is side pixels.
+func downscale(img image.Image, side int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if max(w, h) <= side {
+		return img
+	}
+	if w >= h {
+		w, h = side, max(1, h*side/w)
+	} else {
+		w, h = max(1, w*side/h), side
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
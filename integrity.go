@@ -0,0 +1,309 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+type IssueSeverity string
+
+const (
+	ISSUE_ERROR   IssueSeverity = "error"   // breaks PDF generation
+	ISSUE_WARNING IssueSeverity = "warning" // wastes space or hides data
+)
+
+type FixAction string
+
+const (
+	FIX_REMOVE_MODEL   FixAction = "remove_model"   // drop the entry from models.json
+	FIX_CLEAR_MODEL    FixAction = "clear_model"    // unset a machote reference
+	FIX_SET_MODEL      FixAction = "set_model"      // give the asset the machote in Path
+	FIX_TRASH_ASSET    FixAction = "trash_asset"    // move the asset to the trash
+	FIX_TRASH_PROJECT  FixAction = "trash_project"  // move the project folder to the trash
+	FIX_REGISTER_MODEL FixAction = "register_model" // list the image in models.json again
+	FIX_DELETE_FILE    FixAction = "delete_file"    // delete the orphaned image
+)
+
+// WorkspaceIssue is one problem found by CheckWorkspace. Id is stable
+// across scans so the issues picked by the user can be passed back to
+// RepairWorkspace.
+type WorkspaceIssue struct {
+	Id        string        `json:"id"`
+	Severity  IssueSeverity `json:"severity"`
+	Message   string        `json:"message"`
+	ProjectId string        `json:"projectId"`
+	AssetId   string        `json:"assetId"`
+	Path      string        `json:"path"`
+	Fix       FixAction     `json:"fix"`
+	FixLabel  string        `json:"fixLabel"`
+}
+
+func newIssue(sev IssueSeverity, fix FixAction, projectId, assetId, path, label, msg string) WorkspaceIssue {
+	return WorkspaceIssue{
+		Id:        strings.Join([]string{string(fix), projectId, assetId, path}, "|"),
+		Severity:  sev,
+		Message:   msg,
+		ProjectId: projectId,
+		AssetId:   assetId,
+		Path:      path,
+		Fix:       fix,
+		FixLabel:  label,
+	}
+}
+
+// noModel reports whether ref leaves the asset without a machote.
+func noModel(ref string) bool {
+	return ref == "" || ref == "empty"
+}
+
+// checkWorkspace scans models.json, the model images and every project.
+// Unlike loadModelLibrary it creates nothing, a missing library is empty.
+func checkWorkspace(base string) ([]WorkspaceIssue, error) {
+	var models []Model
+	if err := readJSON(filepath.Join(base, "models", "models.json"), &models); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("models.json is unreadable: %w", err)
+	}
+	imagesDir := filepath.Join(base, "models", "images")
+	issues := []WorkspaceIssue{}
+	exists := func(p string) bool {
+		_, err := os.Stat(p)
+		return err == nil
+	}
+
+	for _, m := range models {
+		if m.Value == "empty" || exists(m.Value) {
+			continue
+		}
+		issues = append(issues, newIssue(ISSUE_ERROR, FIX_REMOVE_MODEL, "", "", m.Value,
+			"Quitar el machote de la lista",
+			fmt.Sprintf("machote %q points at missing image %s", m.Label, m.Value)))
+	}
+
+	// assets without a machote are offered the first one of the library
+	var fallback *Model
+	for _, m := range models {
+		if !noModel(m.Value) && exists(m.Value) {
+			fallback = &m
+			break
+		}
+	}
+
+	// machotes referenced by projects, so orphans still in use are relisted
+	// instead of deleted
+	referenced := map[string]bool{}
+	checkModel := func(p *Project, assetId, ref string) {
+		if noModel(ref) {
+			return
+		}
+		referenced[ref] = true
+		if !exists(ref) {
+			issues = append(issues, newIssue(ISSUE_ERROR, FIX_CLEAR_MODEL, p.Id, assetId, ref,
+				"Quitar la referencia al machote",
+				fmt.Sprintf("project %q uses missing machote %s", p.Name, ref)))
+		}
+	}
+
+	dirs, err := os.ReadDir(filepath.Join(base, "projects"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for _, d := range cleanupDirs(dirs) {
+		id := strings.TrimPrefix(d.Name(), "project-")
+		p, err := readProject(base, id)
+		if err != nil {
+			issues = append(issues, newIssue(ISSUE_ERROR, FIX_TRASH_PROJECT, id, "", filepath.Join(base, "projects", d.Name()),
+				"Mover el proyecto a la papelera",
+				fmt.Sprintf("project %s cannot be read: %v", id, err)))
+			continue
+		}
+		checkModel(p, "", p.DefaultModel)
+		for _, as := range p.Assets {
+			checkModel(p, as.ID, as.Model)
+			if noModel(as.Model) && noModel(p.DefaultModel) {
+				is := newIssue(ISSUE_WARNING, "", p.Id, as.ID, "", "",
+					fmt.Sprintf("asset %s of project %q has no machote", as.ID, p.Name))
+				if fallback != nil {
+					is = newIssue(ISSUE_WARNING, FIX_SET_MODEL, p.Id, as.ID, fallback.Value,
+						concat("Usar el machote ", fallback.Label), is.Message)
+				}
+				issues = append(issues, is)
+			}
+			images := [][2]string{{"sheet", as.Sheet}}
+			if len(as.CutoutRegions) == 0 {
+				images = append(images, [2]string{"cutout", as.Cutout})
+			}
+			for i, c := range as.Continuations {
+				images = append(images, [2]string{fmt.Sprintf("continuation %d sheet", i+1), c.Sheet})
+				if c.Cutout != "" {
+					images = append(images, [2]string{fmt.Sprintf("continuation %d cutout", i+1), c.Cutout})
+				}
+			}
+			for _, f := range images {
+				if _, err := decodeImage(f[1]); err != nil {
+					issues = append(issues, newIssue(ISSUE_ERROR, FIX_TRASH_ASSET, p.Id, as.ID, "",
+						"Mover el recorte a la papelera",
+						fmt.Sprintf("asset %s of project %q has an unreadable %s: %v", as.ID, p.Name, f[0], err)))
+					break
+				}
+			}
+		}
+	}
+
+	files, err := os.ReadDir(imagesDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for _, f := range files {
+		path := filepath.Join(imagesDir, f.Name())
+		if f.IsDir() || strings.HasSuffix(f.Name(), ".tmp") {
+			continue
+		}
+		if slices.ContainsFunc(models, func(m Model) bool { return m.Value == path }) {
+			continue
+		}
+		if referenced[path] {
+			issues = append(issues, newIssue(ISSUE_WARNING, FIX_REGISTER_MODEL, "", "", path,
+				"Volver a listar el machote",
+				fmt.Sprintf("%s is used by projects but missing from models.json", f.Name())))
+			continue
+		}
+		issues = append(issues, newIssue(ISSUE_WARNING, FIX_DELETE_FILE, "", "", path,
+			"Borrar la imagen",
+			fmt.Sprintf("%s is not used by any machote", f.Name())))
+	}
+
+	slices.SortStableFunc(issues, func(a, b WorkspaceIssue) int {
+		if a.Severity != b.Severity {
+			return strings.Compare(string(a.Severity), string(b.Severity))
+		}
+		return strings.Compare(a.Id, b.Id)
+	})
+	return issues, nil
+}
+
+// CheckWorkspace reports the problems in the active workspace, errors
+// first, each with the fix RepairWorkspace would apply.
+func (a *App) CheckWorkspace() ([]WorkspaceIssue, error) {
+	base, err := a.workspaceDir()
+	if err != nil {
+		return nil, err
+	}
+	return checkWorkspace(base)
+}
+
+// RepairWorkspace applies the fixes of the issues listed by id, rescanning
+// first so stale ids are skipped, and returns what is left afterwards.
+func (a *App) RepairWorkspace(fixes []string) ([]WorkspaceIssue, error) {
+	base, err := a.workspaceDir()
+	if err != nil {
+		return nil, err
+	}
+	issues, err := checkWorkspace(base)
+	if err != nil {
+		return nil, err
+	}
+	lib, err := loadModelLibrary(base)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	libChanged := false
+	// project edits are batched so a project is read and written once
+	projects := map[string]*Project{}
+	project := func(id string) (*Project, error) {
+		if p, ok := projects[id]; ok {
+			return p, nil
+		}
+		p, err := readProject(base, id)
+		if err != nil {
+			return nil, err
+		}
+		projects[id] = p
+		return p, nil
+	}
+
+	for _, is := range issues {
+		if !slices.Contains(fixes, is.Id) {
+			continue
+		}
+		switch is.Fix {
+		case FIX_REMOVE_MODEL:
+			lib.models = slices.DeleteFunc(lib.models, func(m Model) bool { return m.Value == is.Path })
+			libChanged = true
+		case FIX_REGISTER_MODEL:
+			lib.models = append(lib.models, Model{Label: filepath.Base(is.Path), Value: is.Path})
+			libChanged = true
+		case FIX_DELETE_FILE:
+			if err := os.Remove(is.Path); err != nil {
+				errs = append(errs, err)
+			}
+		case FIX_CLEAR_MODEL:
+			p, err := project(is.ProjectId)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if p.DefaultModel == is.Path {
+				p.DefaultModel = ""
+			}
+			for i := range p.Assets {
+				if p.Assets[i].Model == is.Path {
+					p.Assets[i].Model = ""
+				}
+			}
+		case FIX_SET_MODEL:
+			p, err := project(is.ProjectId)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if i := slices.IndexFunc(p.Assets, func(as AssetMetadata) bool { return as.ID == is.AssetId }); i != -1 {
+				p.Assets[i].Model = is.Path
+				p.Assets[i].UpdatedAt = timestamp()
+			}
+		case FIX_TRASH_ASSET:
+			p, err := project(is.ProjectId)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			i := slices.IndexFunc(p.Assets, func(as AssetMetadata) bool { return as.ID == is.AssetId })
+			if i == -1 {
+				continue
+			}
+			if err := trashAsset(base, *p, p.Assets[i]); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			p.Assets = slices.Delete(p.Assets, i, i+1)
+		case FIX_TRASH_PROJECT:
+			if err := trashProject(base, is.ProjectId, is.ProjectId); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if err := unindexProject(base, is.ProjectId); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if libChanged {
+		if err := lib.save(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, p := range projects {
+		if err := writeProject(base, p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("some fixes failed: %w", err)
+	}
+	return checkWorkspace(base)
+}
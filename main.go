@@ -19,16 +19,24 @@ func main() {
 	// Create an instance of the app structure
 	app := NewApp()
 
+	width, height := 1024, 768
+	if s, err := currentSettings(); err == nil {
+		width, height = s.WindowWidth, s.WindowHeight
+	} else {
+		println("Error:", err.Error())
+	}
+
 	// Create application with options
 	err := wails.Run(&options.App{
 		Title:  "Hyuga",
-		Width:  1024,
-		Height: 768,
+		Width:  width,
+		Height: height,
 		AssetServer: &assetserver.Options{
 			Assets: assets,
 		},
 		BackgroundColour: &options.RGBA{R: 228, G: 228, B: 228, A: 1},
 		OnStartup:        app.startup,
+		OnBeforeClose:    app.beforeClose,
 		Bind: []interface{}{
 			app,
 		},
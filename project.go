@@ -1,18 +1,25 @@
 package main
 
 import (
+	"bytes"
+	"cmp"
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"image"
 	"image/draw"
+	"image/jpeg"
 	"math"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/signintech/gopdf"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
@@ -32,10 +39,91 @@ type AssetMetadata struct {
 	PageNumber string `json:"pageNumber"`
 	Section    string `json:"section"`
 	Model      string `json:"model"`
+	// SheetEdits and CutoutEdits are applied to the images when rendering,
+	// the stored images are never modified.
+	SheetEdits  []ImageEdit `json:"sheetEdits,omitempty"`
+	CutoutEdits []ImageEdit `json:"cutoutEdits,omitempty"`
+	// CutoutRegions, when set, cut the cutout out of the sheet and Cutout
+	// may be empty. HighlightCutout highlights them on the sheet page.
+	CutoutRegions   []Region    `json:"cutoutRegions,omitempty"`
+	HighlightCutout bool        `json:"highlightCutout"`
+	Highlights      []Highlight `json:"highlights,omitempty"`
+	// SheetAnnotations and CutoutAnnotations are drawn over the images.
+	SheetAnnotations  []Annotation `json:"sheetAnnotations,omitempty"`
+	CutoutAnnotations []Annotation `json:"cutoutAnnotations,omitempty"`
+	// SheetRedactions and CutoutRedactions are burned into the images of
+	// reports exported with the project's RedactedExport on.
+	SheetRedactions  []Redaction `json:"sheetRedactions,omitempty"`
+	CutoutRedactions []Redaction `json:"cutoutRedactions,omitempty"`
+	// Enhance overrides the project's enhancement preset when set. Baked
+	// is the preset the images were enhanced with at upload, if they were.
+	Enhance EnhancePreset `json:"enhance,omitempty"`
+	Baked   EnhancePreset `json:"baked,omitempty"`
+	// Continuations are the further pages the article runs on.
+	Continuations []Continuation `json:"continuations,omitempty"`
+	// Publication, EditionDate, Headline, Byline, MediaType and URL say
+	// where and when the clipping was published. EditionDate is YYYY-MM-DD
+	// and defaults to the day the sheet was photographed. PublicationId,
+	// when set, links the publication to the registry, which then names
+	// it and lists the sections to pick from.
+	PublicationId string    `json:"publicationId,omitempty"`
+	Publication   string    `json:"publication"`
+	EditionDate   string    `json:"editionDate"`
+	Headline      string    `json:"headline"`
+	Byline        string    `json:"byline"`
+	MediaType     MediaType `json:"mediaType"`
+	URL           string    `json:"url"`
+	// Fields holds the values of the project's custom fields by key.
+	Fields    map[string]any `json:"fields,omitempty"`
+	Capture   *CaptureInfo   `json:"capture,omitempty"`
+	CreatedAt string         `json:"createdAt"`
+	UpdatedAt string         `json:"updatedAt"`
+}
+
+// CaptureInfo is what the camera recorded about the photo of the sheet.
+// It is read from EXIF at upload and never edited.
+type CaptureInfo struct {
+	TakenAt string       `json:"takenAt"`
+	Device  string       `json:"device"`
+	GPS     *GPSPosition `json:"gps,omitempty"`
+}
+
+type GPSPosition struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Altitude  float64 `json:"altitude"` // meters
 }
 
 type Region struct {
-	X, Y, W, H float64
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	W float64 `json:"w"`
+	H float64 `json:"h"`
+}
+
+// ProjectLayout controls how the machote and the cutout share the
+// clipping page.
+type ProjectLayout struct {
+	ModelRatio float64 `json:"modelRatio"` // share of the content height given to the machote
+	Gap        float64 `json:"gap"`        // space between machote and cutout, in points
+}
+
+func defaultLayout() ProjectLayout {
+	return ProjectLayout{ModelRatio: 0.40, Gap: 12}
+}
+
+// lastModel returns the machote of the last upload when the workspace at
+// base has it. Settings are shared by every workspace.
+func lastModel(base string, s *Settings) string {
+	ref := s.LastModel
+	relinkModel(base, &ref)
+	if filepath.Dir(ref) != filepath.Join(base, "models", "images") {
+		return ""
+	}
+	if _, err := os.Stat(ref); err != nil {
+		return ""
+	}
+	return ref
 }
 
 func (a *App) UploadAsset(projectId string, as AssetMetadata) error {
@@ -43,40 +131,122 @@ func (a *App) UploadAsset(projectId string, as AssetMetadata) error {
 		return fmt.Errorf("projectId and assetId are required")
 	}
 
-	base, err := getBaseConfigPath()
+	base, err := a.workspaceDir()
 	if err != nil {
 		return err
 	}
 
-	projectPath := filepath.Join(base, "projects", concat("project-", projectId), "project.json")
-	b, err := os.ReadFile(projectPath)
+	proj, err := readProject(base, projectId)
 	if err != nil {
-		return fmt.Errorf("failed to read project.json: %w", err)
-	}
-
-	var proj Project
-	if err := json.Unmarshal(b, &proj); err != nil {
-		return fmt.Errorf("invalid project JSON: %w", err)
+		return err
 	}
 
-	ok := ensureUnique(proj, as)
+	ok := ensureUnique(*proj, as)
 	if !ok {
 		fmt.Println(concat("project: ", proj.Id, " already exists"))
 		return nil
 	}
 
-	proj.Assets = append(proj.Assets, as)
-
-	data, err := json.MarshalIndent(proj, "", "  ")
+	settings, err := currentSettings()
 	if err != nil {
 		return err
 	}
-
-	err = os.WriteFile(projectPath, data, 0644)
+	opts := ingestOptionsFrom(settings)
+	var sheetExif, cutoutExif exifData
+	if as.Sheet, sheetExif, err = ingestImageRef(string(SHEET), as.Sheet, opts); err != nil {
+		return err
+	}
+	if err := validateRegions(as.CutoutRegions); err != nil {
+		return err
+	}
+	for i, h := range as.Highlights {
+		if err := h.validate(); err != nil {
+			return fmt.Errorf("highlight %d: %w", i+1, err)
+		}
+	}
+	for i, an := range slices.Concat(as.SheetAnnotations, as.CutoutAnnotations) {
+		if err := an.validate(); err != nil {
+			return fmt.Errorf("annotation %d: %w", i+1, err)
+		}
+	}
+	for i, r := range slices.Concat(as.SheetRedactions, as.CutoutRedactions) {
+		if err := r.validate(); err != nil {
+			return fmt.Errorf("redaction %d: %w", i+1, err)
+		}
+	}
+	if as.Cutout != "" || len(as.CutoutRegions) == 0 {
+		if as.Cutout, cutoutExif, err = ingestImageRef(string(CUTOUT), as.Cutout, opts); err != nil {
+			return err
+		}
+	}
+	if err := ingestContinuations(as.Continuations, opts); err != nil {
+		return err
+	}
+	if err := as.Enhance.validate(true); err != nil {
+		return err
+	}
+	as.Baked = ""
+	if proj.EnhanceOnUpload {
+		preset := as.enhancePreset(proj.Enhance)
+		refs := []*string{&as.Sheet, &as.Cutout}
+		for i := range as.Continuations {
+			refs = append(refs, &as.Continuations[i].Sheet, &as.Continuations[i].Cutout)
+		}
+		for _, ref := range refs {
+			if *ref == "" {
+				continue
+			}
+			if *ref, err = bakeEnhance(*ref, preset, opts.Quality); err != nil {
+				return err
+			}
+		}
+		as.Enhance = PRESET_NONE
+		as.Baked = preset
+	}
+	// the cutout is often a crop of the same photo, saved without metadata
+	as.Capture = captureInfo(sheetExif)
+	if as.Capture == nil {
+		as.Capture = captureInfo(cutoutExif)
+	}
+	if strings.TrimSpace(as.EditionDate) == "" && as.Capture != nil && as.Capture.TakenAt != "" {
+		as.EditionDate = as.Capture.TakenAt[:len(time.DateOnly)]
+	}
+	pubModel, err := applyPublication(base, &as)
 	if err != nil {
 		return err
 	}
+	if err := as.cleanClipping(); err != nil {
+		return err
+	}
+	if as.Fields, err = cleanFieldValues(proj.Fields, as.Fields); err != nil {
+		return err
+	}
+	if as.Model == "" || as.Model == "empty" {
+		as.Model = cmp.Or(pubModel, proj.DefaultModel, lastModel(base, settings))
+	}
+	if as.Model != "" {
+		if err := checkImageFile("model", as.Model); err != nil {
+			return err
+		}
+	}
+	as.CreatedAt = timestamp()
+	as.UpdatedAt = as.CreatedAt
+	proj.Assets = append(proj.Assets, as)
 
+	if err := writeProject(base, proj); err != nil {
+		return err
+	}
+	cacheThumbnails(base, as.images()...)
+	if as.Model != "" && as.Model != proj.DefaultModel {
+		_, err := updateSettings(func(s *Settings) error {
+			s.LastModel = as.Model
+			return nil
+		})
+		// the asset is stored, failing the upload would only invite a duplicate
+		if err != nil {
+			fmt.Println(concat("could not remember the machote: ", err.Error()))
+		}
+	}
 	return nil
 }
 
@@ -85,44 +255,92 @@ func (a *App) LoadAssets(projectId string) ([]AssetMetadata, error) {
 		return nil, fmt.Errorf("projectId required")
 	}
 
-	base, err := getBaseConfigPath()
+	base, err := a.workspaceDir()
 	if err != nil {
 		return nil, err
 	}
 
-	projectPath := filepath.Join(base, "projects", concat("project-", projectId), "project.json")
-	b, err := os.ReadFile(projectPath)
+	proj, err := readProject(base, projectId)
 	if err != nil {
-		return nil, fmt.Errorf("cannot read project folder: %w", err)
-	}
-
-	var proj Project
-	if err := json.Unmarshal(b, &proj); err != nil {
-		return nil, fmt.Errorf("invalid project JSON %w", err)
+		return nil, err
 	}
 
 	return proj.Assets, nil
 }
 
+// AssetSummary is an asset without its images, which GetThumbnail serves
+// by hash, or by path for the machote. A cutout cut from the sheet has no
+// CutoutHash.
+type AssetSummary struct {
+	ID          string   `json:"id"`
+	PageNumber  string   `json:"pageNumber"`
+	ContinuedOn []string `json:"continuedOn,omitempty"`
+	Section     string   `json:"section"`
+	Model       string   `json:"model"`
+	Publication string   `json:"publication"`
+	Headline    string   `json:"headline"`
+	EditionDate string   `json:"editionDate"`
+	SheetHash   string   `json:"sheetHash"`
+	CutoutHash  string   `json:"cutoutHash"`
+	CreatedAt   string   `json:"createdAt"`
+	UpdatedAt   string   `json:"updatedAt"`
+}
+
+// LoadAssetSummaries lists the assets of a project for views that only
+// show thumbnails.
+func (a *App) LoadAssetSummaries(projectId string) ([]AssetSummary, error) {
+	if projectId == "" {
+		return nil, fmt.Errorf("projectId required")
+	}
+	base, err := a.workspaceDir()
+	if err != nil {
+		return nil, err
+	}
+	proj, err := readProject(base, projectId)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]AssetSummary, 0, len(proj.Assets))
+	for _, as := range proj.Assets {
+		s := AssetSummary{
+			ID:          as.ID,
+			PageNumber:  as.PageNumber,
+			Section:     as.Section,
+			Model:       as.Model,
+			Publication: as.Publication,
+			Headline:    as.Headline,
+			EditionDate: as.EditionDate,
+			CreatedAt:   as.CreatedAt,
+			UpdatedAt:   as.UpdatedAt,
+		}
+		// a broken image only costs its thumbnail
+		s.SheetHash, _ = imageHash(as.Sheet)
+		if as.Cutout != "" {
+			s.CutoutHash, _ = imageHash(as.Cutout)
+		}
+		for _, c := range as.Continuations {
+			s.ContinuedOn = append(s.ContinuedOn, c.PageNumber)
+		}
+		res = append(res, s)
+	}
+	return res, nil
+}
+
+// DeleteAsset removes the asset from its project and keeps a copy in the
+// trash, see RestoreFromTrash.
 func (a *App) DeleteAsset(projectId string, assetId string) error {
 	if projectId == "" || assetId == "" {
 		return fmt.Errorf("required project or asset ID not found")
 	}
 
-	base, err := getBaseConfigPath()
+	base, err := a.workspaceDir()
 	if err != nil {
 		return err
 	}
 
-	projectPath := filepath.Join(base, "projects", concat("project-", projectId), "project.json")
-	b, err := os.ReadFile(projectPath)
+	proj, err := readProject(base, projectId)
 	if err != nil {
-		return fmt.Errorf("cannot read project folder: %w", err)
-	}
-
-	var proj Project
-	if err := json.Unmarshal(b, &proj); err != nil {
-		return fmt.Errorf("invalid project JSON %w", err)
+		return err
 	}
 
 	index := -1
@@ -137,18 +355,52 @@ func (a *App) DeleteAsset(projectId string, assetId string) error {
 		return fmt.Errorf("asset with ID %s not found in project", assetId)
 	}
 
+	if err := trashAsset(base, *proj, proj.Assets[index]); err != nil {
+		return err
+	}
+
 	proj.Assets[index] = proj.Assets[len(proj.Assets)-1]
 	proj.Assets = proj.Assets[:len(proj.Assets)-1]
 
-	updated, err := json.MarshalIndent(proj, "", "  ")
+	return writeProject(base, proj)
+}
+
+var dataURLRe = regexp.MustCompile(`^data:(?P<mime>[-\w.+/]+)?(?:;charset=[\w-]+)?;base64,`)
+
+// imageBytes returns the raw bytes of an image reference, which is either
+// a base64 payload (optionally a data URL) or the path of a file saved in
+// the workspace, as machotes are.
+func imageBytes(ref string) ([]byte, error) {
+	ref = strings.TrimSpace(ref)
+	if filepath.IsAbs(ref) {
+		return os.ReadFile(ref)
+	}
+	if m := dataURLRe.FindStringIndex(ref); m != nil {
+		ref = ref[m[1]:]
+	}
+	return base64.StdEncoding.DecodeString(ref)
+}
+
+func decodeImage(ref string) (image.Image, error) {
+	b, err := imageBytes(ref)
 	if err != nil {
-		return fmt.Errorf("failed to serialize updated project: %w", err)
+		return nil, err
 	}
+	img, _, err := image.Decode(bytes.NewReader(b))
+	return img, err
+}
 
-	if err := os.WriteFile(projectPath, updated, 0644); err != nil {
-		return fmt.Errorf("failed to write updated project file: %w", err)
+// imageHash identifies an image by the sha256 of its raw bytes.
+func imageHash(ref string) (string, error) {
+	if h, ok := hashFromSavedPath(ref); ok && filepath.IsAbs(ref) {
+		return h, nil
 	}
-	return nil
+	b, err := imageBytes(ref)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
 }
 
 func toRGBA(src image.Image) *image.RGBA {
@@ -179,10 +431,10 @@ func fitWithinRegion(imgWpx, imgHpx int, region Region, padding float64) (x, y f
 	return x, y, &gopdf.Rect{W: drawW, H: drawH}
 }
 
-func fitWithinA4(imgWpx, imgHpx int) (x, y float64, r *gopdf.Rect) {
-	margin := 36.0 // 0.5 inch margins
-	maxW := gopdf.PageSizeA4.W - 2*margin
-	maxH := gopdf.PageSizeA4.H - 2*margin
+func fitWithinPage(imgWpx, imgHpx int, ps pageSetup) (x, y float64, r *gopdf.Rect) {
+	margin := ps.Margin
+	maxW := ps.W - 2*margin
+	maxH := ps.H - 2*margin
 
 	iw := float64(imgWpx)
 	ih := float64(imgHpx)
@@ -196,17 +448,29 @@ func fitWithinA4(imgWpx, imgHpx int) (x, y float64, r *gopdf.Rect) {
 	drawW := iw * scale
 	drawH := ih * scale
 
-	x = (gopdf.PageSizeA4.W - drawW) / 2.0
-	y = (gopdf.PageSizeA4.H - drawH) / 2.0
+	x = (ps.W - drawW) / 2.0
+	y = (ps.H - drawH) / 2.0
 	return x, y, &gopdf.Rect{W: drawW, H: drawH}
 }
 
-func configSavePath(ctx context.Context, proj Project) (string, error) {
-	homeDir, err := os.UserHomeDir()
+// placeImage embeds img as a JPEG of the configured quality. Images with
+// transparency, typically machotes, stay PNG so they keep their alpha.
+func placeImage(pdf *gopdf.GoPdf, img image.Image, x, y float64, rect *gopdf.Rect, quality int) error {
+	if o, ok := img.(interface{ Opaque() bool }); !ok || !o.Opaque() {
+		return pdf.ImageFrom(img, x, y, rect)
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return err
+	}
+	h, err := gopdf.ImageHolderByBytes(buf.Bytes())
 	if err != nil {
-		return "", err
+		return err
 	}
-	dir := filepath.Join(homeDir, "Downloads")
+	return pdf.ImageByHolder(h, x, y, rect)
+}
+
+func configSavePath(ctx context.Context, proj Project, dir string) (string, error) {
 	dialogOpts := runtime.SaveDialogOptions{
 		DefaultDirectory:           dir,
 		DefaultFilename:            concat(proj.Name, ".pdf"),
@@ -223,111 +487,199 @@ func configSavePath(ctx context.Context, proj Project) (string, error) {
 	return path, nil
 }
 
-func (a *App) GeneratePDF(projectId string) error {
-	if projectId == "" {
-		return fmt.Errorf("required project or asset ID not found")
-	}
+func drawCover(pdf *gopdf.GoPdf, proj Project, ps pageSetup) error {
+	const lineH = 22.0
+	margin := ps.Margin
+	w := ps.W - 2*margin
+	pdf.AddPage()
 
-	base, err := getBaseConfigPath()
-	if err != nil {
+	if err := pdf.SetFont("times", "", 28); err != nil {
 		return err
 	}
-
-	projectPath := filepath.Join(base, "projects", concat("project-", projectId), "project.json")
-	b, err := os.ReadFile(projectPath)
-	if err != nil {
-		return fmt.Errorf("cannot read project folder: %w", err)
-	}
-
-	var proj Project
-	if err := json.Unmarshal(b, &proj); err != nil {
-		return fmt.Errorf("invalid project JSON %w", err)
+	pdf.SetXY(margin, ps.H/3)
+	if err := pdf.CellWithOption(&gopdf.Rect{W: w, H: 36}, proj.Name, gopdf.CellOption{Align: gopdf.Center}); err != nil {
+		return err
 	}
+	pdf.Br(48)
 
-	path, err := configSavePath(a.ctx, proj)
-	if err != nil {
+	if err := pdf.SetFont("times", "", 14); err != nil {
 		return err
 	}
-	if path == "" {
-		return errors.New("no file path provided")
+	period := strings.Trim(strings.Join([]string{proj.PeriodStart, proj.PeriodEnd}, " – "), " –")
+	rows := [][2]string{
+		{tr(ps.Lang, "client"), proj.Client},
+		{tr(ps.Lang, "period"), period},
+		{tr(ps.Lang, "status"), tr(ps.Lang, string(proj.Status))},
+		{tr(ps.Lang, "tags"), strings.Join(proj.Tags, ", ")},
+	}
+	for _, r := range rows {
+		if r[1] == "" {
+			continue
+		}
+		pdf.SetX(margin)
+		if err := pdf.CellWithOption(&gopdf.Rect{W: w, H: lineH}, concat(r[0], ": ", r[1]), gopdf.CellOption{Align: gopdf.Center}); err != nil {
+			return err
+		}
+		pdf.Br(lineH)
+	}
+
+	if proj.Description != "" {
+		pdf.Br(lineH)
+		pdf.SetX(margin)
+		if err := pdf.MultiCellWithOption(&gopdf.Rect{W: w, H: lineH}, proj.Description, gopdf.CellOption{Align: gopdf.Center}); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
+// buildPDF lays out the report: the cover, then for every asset its full
+// sheets followed by the machote and cutout page and, for articles that
+// continue on other pages, a page for each further cutout.
+func buildPDF(base string, proj Project, ps pageSetup) (*gopdf.GoPdf, error) {
 	pdf := &gopdf.GoPdf{}
-	pageSize := *gopdf.PageSizeA4
-	pdf.Start(gopdf.Config{PageSize: pageSize})
+	pdf.Start(gopdf.Config{PageSize: gopdf.Rect{W: ps.W, H: ps.H}})
 
 	fontPath := filepath.Join("fonts", "times.ttf")
 	if err := pdf.AddTTFFont("times", fontPath); err != nil {
-		return fmt.Errorf("failed to load font: %w", err)
+		return nil, fmt.Errorf("failed to load font: %w", err)
 	}
 
+	if err := drawCover(pdf, proj, ps); err != nil {
+		return nil, fmt.Errorf("failed to draw cover: %w", err)
+	}
+
+	ro := renderOptionsFor(base, proj)
 	for _, v := range proj.Assets {
-		pdf.AddPage()
-		var dataURLRe = regexp.MustCompile(`^data:(?P<mime>[-\w.+/]+)?(?:;charset=[\w-]+)?;base64,`)
-		if m := dataURLRe.FindStringIndex(v.Sheet); m != nil {
-			v.Sheet = v.Sheet[m[1]:]
-		}
-		if m := dataURLRe.FindStringIndex(v.Cutout); m != nil {
-			v.Cutout = v.Cutout[m[1]:]
+		if v.Model == "" {
+			v.Model = proj.DefaultModel
 		}
-		if m := dataURLRe.FindStringIndex(v.Model); m != nil {
-			v.Model = v.Model[m[1]:]
-		}
-		sheetReader := base64.NewDecoder(base64.StdEncoding, strings.NewReader(strings.TrimSpace(v.Sheet)))
-		cutoutReader := base64.NewDecoder(base64.StdEncoding, strings.NewReader(strings.TrimSpace(v.Cutout)))
-		modelReader := base64.NewDecoder(base64.StdEncoding, strings.NewReader(strings.TrimSpace(v.Model)))
-		sheetImg, _, err := image.Decode(sheetReader)
-		if err != nil {
-			return err
-		}
-		cutoutImg, _, err := image.Decode(cutoutReader)
-		if err != nil {
-			return err
-		}
-		modelImg, _, err := image.Decode(modelReader)
-		if err != nil {
-			return err
+		pages := v.pages()
+		for i, page := range pages {
+			sheetImg, err := assetImage(page, SHEET, ro)
+			if err != nil {
+				return nil, err
+			}
+			pdf.AddPage()
+			sheetRgba := toRGBA(sheetImg)
+			sx, sy, srect := fitWithinPage(sheetRgba.Bounds().Dx(), sheetRgba.Bounds().Dy(), ps)
+			if err := placeImage(pdf, sheetRgba, sx, sy, srect, ps.Quality); err != nil {
+				return nil, err
+			}
+			if i > 0 {
+				continue
+			}
+			if err := drawHighlights(pdf, sheetHighlights(v), proj.HighlightStyle, sx, sy, srect); err != nil {
+				return nil, err
+			}
+			if err := drawAnnotations(pdf, v.SheetAnnotations, sx, sy, srect); err != nil {
+				return nil, err
+			}
 		}
 
-		sheetRgba := toRGBA(sheetImg)
-		cutoutRgba := toRGBA(cutoutImg)
-		modelRgba := toRGBA(modelImg)
-		sx, sy, srect := fitWithinA4(sheetRgba.Bounds().Dx(), sheetRgba.Bounds().Dy())
-		if err := pdf.ImageFrom(sheetRgba, sx, sy, srect); err != nil {
-			return err
-		}
-		pdf.AddPage()
-		const margin = 36.0
-		const gap = 12.0
-		pageW := gopdf.PageSizeA4.W
-		pageH := gopdf.PageSizeA4.H
-		contentW := pageW - 2*margin
-		contentH := pageH - 2*margin
-		topH := contentH * 0.40
-		midY := margin + topH + gap
-		midH := contentH - topH - gap
-		topRegion := Region{
-			X: margin,
-			Y: margin,
-			W: contentW,
-			H: topH,
-		}
-		midRegion := Region{
-			X: margin,
-			Y: midY,
-			W: contentW,
-			H: midH,
-		}
-		const innerPad = 4.0
-		mx, my, mrect := fitWithinRegion(modelRgba.Bounds().Dx(), modelRgba.Bounds().Dy(), topRegion, innerPad)
-		if err := pdf.ImageFrom(modelRgba, mx, my, mrect); err != nil {
-			return err
+		// the first page always has a cutout, continuations may not
+		cutouts := slices.DeleteFunc(pages, func(p AssetMetadata) bool {
+			return p.Cutout == "" && len(p.CutoutRegions) == 0
+		})
+		for i, page := range cutouts {
+			cutoutImg, err := assetImage(page, CUTOUT, ro)
+			if err != nil {
+				return nil, err
+			}
+			cutoutRgba := toRGBA(cutoutImg)
+			pdf.AddPage()
+			clipping := ""
+			if i == 0 {
+				clipping = clippingCaption(ps.Lang, v, proj.Fields)
+			}
+			caption, err := captionLines(pdf, ps, clipping, continuationCaption(ps.Lang, cutouts, i))
+			if err != nil {
+				return nil, err
+			}
+			margin := ps.Margin
+			gap := proj.Layout.Gap
+			contentW := ps.W - 2*margin
+			contentH := ps.H - 2*margin - captionH*float64(len(caption))
+			const innerPad = 4.0
+			// the machote heads the first cutout page only, when there is one
+			midRegion := Region{X: margin, Y: margin, W: contentW, H: contentH}
+			if i == 0 && !noModel(v.Model) {
+				modelImg, err := decodeImage(v.Model)
+				if err != nil {
+					return nil, err
+				}
+				modelRgba := toRGBA(modelImg)
+				topH := contentH * proj.Layout.ModelRatio
+				topRegion := Region{
+					X: margin,
+					Y: margin,
+					W: contentW,
+					H: topH,
+				}
+				midRegion = Region{
+					X: margin,
+					Y: margin + topH + gap,
+					W: contentW,
+					H: contentH - topH - gap,
+				}
+				mx, my, mrect := fitWithinRegion(modelRgba.Bounds().Dx(), modelRgba.Bounds().Dy(), topRegion, innerPad)
+				if err := placeImage(pdf, modelRgba, mx, my, mrect, ps.Quality); err != nil {
+					return nil, err
+				}
+			}
+
+			cx, cy, crect := fitWithinRegion(cutoutRgba.Bounds().Dx(), cutoutRgba.Bounds().Dy(), midRegion, innerPad)
+			if err := placeImage(pdf, cutoutRgba, cx, cy, crect, ps.Quality); err != nil {
+				return nil, err
+			}
+			if i == 0 {
+				if err := drawAnnotations(pdf, v.CutoutAnnotations, cx, cy, crect); err != nil {
+					return nil, err
+				}
+			}
+			if len(caption) > 0 {
+				if err := drawCaption(pdf, caption, ps); err != nil {
+					return nil, err
+				}
+			}
 		}
+	}
 
-		cx, cy, crect := fitWithinRegion(cutoutRgba.Bounds().Dx(), cutoutRgba.Bounds().Dy(), midRegion, innerPad)
-		if err := pdf.ImageFrom(cutoutRgba, cx, cy, crect); err != nil {
-			return err
-		}
+	return pdf, nil
+}
+
+func (a *App) GeneratePDF(projectId string) error {
+	if projectId == "" {
+		return fmt.Errorf("required project or asset ID not found")
+	}
+
+	base, err := a.workspaceDir()
+	if err != nil {
+		return err
+	}
+
+	proj, err := readProject(base, projectId)
+	if err != nil {
+		return err
+	}
+
+	settings, err := currentSettings()
+	if err != nil {
+		return err
+	}
+	ps := settings.pageSetup()
+
+	path, err := configSavePath(a.ctx, *proj, settings.ExportDir)
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		return errors.New("no file path provided")
+	}
+
+	pdf, err := buildPDF(base, *proj, ps)
+	if err != nil {
+		return err
 	}
 
 	if err := pdf.WritePdf(path); err != nil {
@@ -338,7 +690,7 @@ func (a *App) GeneratePDF(projectId string) error {
 }
 
 func (a *App) LoadModels() ([]Model, error) {
-	base, err := getBaseConfigPath()
+	base, err := a.workspaceDir()
 	if err != nil {
 		return nil, err
 	}
@@ -355,5 +707,14 @@ func (a *App) LoadModels() ([]Model, error) {
 		return nil, err
 	}
 
+	// the placeholder follows the current language
+	if s, err := currentSettings(); err == nil {
+		for i := range models {
+			if models[i].Value == "empty" {
+				models[i].Label = tr(s.Language, "placeholder")
+			}
+		}
+	}
+
 	return models, nil
 }
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// The publication registry keeps what is known about every newspaper or
+// magazine once, so assets only pick one: its machote is their default and
+// their section comes from its list. Assets keep the publication name too,
+// so reports and search never have to look the registry up.
+
+type RateUnit string
+
+const (
+	RATE_COLUMN_CM RateUnit = "column_cm" // price of a column one centimeter high
+	RATE_PAGE      RateUnit = "page"      // price of a full page
+)
+
+// SectionRate is the advertising price of a section. The empty section
+// applies to the sections without a rate of their own.
+type SectionRate struct {
+	Section string   `json:"section"`
+	Unit    RateUnit `json:"unit"`
+	Price   float64  `json:"price"`
+}
+
+type Publication struct {
+	Id           string        `json:"id"`
+	Name         string        `json:"name"`
+	DefaultModel string        `json:"defaultModel"`
+	Sections     []string      `json:"sections"`
+	PageWidthCm  float64       `json:"pageWidthCm"`
+	PageHeightCm float64       `json:"pageHeightCm"`
+	Rates        []SectionRate `json:"rates"`
+}
+
+const maxPageCm = 200
+
+func publicationsFile(base string) string {
+	return filepath.Join(base, "publications", "publications.json")
+}
+
+func readPublications(base string) ([]Publication, error) {
+	var ps []Publication
+	if err := readJSON(publicationsFile(base), &ps); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []Publication{}, nil
+		}
+		return nil, fmt.Errorf("invalid publications file: %w", err)
+	}
+	return ps, nil
+}
+
+func writePublications(base string, ps []Publication) error {
+	if err := os.MkdirAll(filepath.Dir(publicationsFile(base)), 0755); err != nil {
+		return err
+	}
+	return writeJSON(publicationsFile(base), ps)
+}
+
+// clean trims p and checks it.
+func (p *Publication) clean() error {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		return errors.New("publication name is required")
+	}
+	if utf8.RuneCountInString(p.Name) > maxNameLength {
+		return fmt.Errorf("publication name is longer than %d characters", maxNameLength)
+	}
+	if p.Sections == nil {
+		p.Sections = []string{}
+	}
+	for i := range p.Sections {
+		p.Sections[i] = strings.TrimSpace(p.Sections[i])
+		if p.Sections[i] == "" || slices.Contains(p.Sections[:i], p.Sections[i]) {
+			return errors.New("sections must be non-empty and unique")
+		}
+	}
+	if p.PageWidthCm < 0 || p.PageWidthCm > maxPageCm || p.PageHeightCm < 0 || p.PageHeightCm > maxPageCm {
+		return fmt.Errorf("page size must be between 0 and %d cm", maxPageCm)
+	}
+	if p.Rates == nil {
+		p.Rates = []SectionRate{}
+	}
+	for i := range p.Rates {
+		r := &p.Rates[i]
+		r.Section = strings.TrimSpace(r.Section)
+		if r.Section != "" && !slices.Contains(p.Sections, r.Section) {
+			return fmt.Errorf("rate for unknown section %q", r.Section)
+		}
+		if slices.ContainsFunc(p.Rates[:i], func(o SectionRate) bool { return o.Section == r.Section }) {
+			return fmt.Errorf("section %q has two rates", r.Section)
+		}
+		if r.Unit != RATE_COLUMN_CM && r.Unit != RATE_PAGE {
+			return fmt.Errorf("unknown rate unit %q", r.Unit)
+		}
+		if r.Price < 0 {
+			return errors.New("prices can't be negative")
+		}
+	}
+	if p.DefaultModel != "" {
+		return checkImageFile("model", p.DefaultModel)
+	}
+	return nil
+}
+
+// applyPublication checks as against the publication it references,
+// fills in the publication name and returns its default machote. Assets
+// of deleted publications, restored from the trash or a backup, are
+// unlinked and keep the name.
+func applyPublication(base string, as *AssetMetadata) (string, error) {
+	if as.PublicationId == "" {
+		return "", nil
+	}
+	ps, err := readPublications(base)
+	if err != nil {
+		return "", err
+	}
+	i := slices.IndexFunc(ps, func(p Publication) bool { return p.Id == as.PublicationId })
+	if i == -1 {
+		as.PublicationId = ""
+		return "", nil
+	}
+	p := ps[i]
+	as.Publication = p.Name
+	if as.Section != "" && len(p.Sections) > 0 && !slices.Contains(p.Sections, as.Section) {
+		return "", fmt.Errorf("section %q is not one of %s", as.Section, p.Name)
+	}
+	return p.DefaultModel, nil
+}
+
+// updatePublicationAssets applies f to every asset referencing the
+// publication id, in every project of base.
+func updatePublicationAssets(base, id string, f func(as *AssetMetadata)) error {
+	idx, err := readSummaries(base)
+	if err != nil {
+		return err
+	}
+	for projectId := range idx {
+		proj, err := readProject(base, projectId)
+		if err != nil {
+			continue
+		}
+		changed := false
+		for i := range proj.Assets {
+			if proj.Assets[i].PublicationId == id {
+				f(&proj.Assets[i])
+				proj.Assets[i].UpdatedAt = timestamp()
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		if err := writeProject(base, proj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// usedSections counts the assets of every project of base in each section
+// of the publication id.
+func usedSections(base, id string) (map[string]int, error) {
+	idx, err := readSummaries(base)
+	if err != nil {
+		return nil, err
+	}
+	used := map[string]int{}
+	for projectId := range idx {
+		proj, err := readProject(base, projectId)
+		if err != nil {
+			continue
+		}
+		for _, as := range proj.Assets {
+			if as.PublicationId == id && as.Section != "" {
+				used[as.Section]++
+			}
+		}
+	}
+	return used, nil
+}
+
+func (a *App) LoadPublications() ([]Publication, error) {
+	base, err := a.workspaceDir()
+	if err != nil {
+		return nil, err
+	}
+	ps, err := readPublications(base)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(ps, func(x, y Publication) int { return strings.Compare(fold(x.Name), fold(y.Name)) })
+	return ps, nil
+}
+
+// SavePublication creates the publication when it has no ID and replaces
+// the stored one otherwise. A new name is carried over to its assets, and
+// sections its assets are in cannot be removed.
+func (a *App) SavePublication(p Publication) (*Publication, error) {
+	if err := p.clean(); err != nil {
+		return nil, err
+	}
+	base, err := a.workspaceDir()
+	if err != nil {
+		return nil, err
+	}
+	ps, err := readPublications(base)
+	if err != nil {
+		return nil, err
+	}
+	if slices.ContainsFunc(ps, func(v Publication) bool { return v.Id != p.Id && strings.EqualFold(v.Name, p.Name) }) {
+		return nil, fmt.Errorf("publication %q already exists", p.Name)
+	}
+	i := slices.IndexFunc(ps, func(v Publication) bool { return v.Id == p.Id })
+	renamed := false
+	switch {
+	case p.Id == "":
+		p.Id = uuid.NewString()
+		ps = append(ps, p)
+	case i == -1:
+		return nil, fmt.Errorf("publication %s not found", p.Id)
+	default:
+		renamed = ps[i].Name != p.Name
+		if len(p.Sections) > 0 {
+			used, err := usedSections(base, p.Id)
+			if err != nil {
+				return nil, err
+			}
+			for _, section := range slices.Sorted(maps.Keys(used)) {
+				if !slices.Contains(p.Sections, section) {
+					return nil, fmt.Errorf("section %q is used by %d assets, move them first", section, used[section])
+				}
+			}
+		}
+		ps[i] = p
+	}
+	if err := writePublications(base, ps); err != nil {
+		return nil, err
+	}
+	if renamed {
+		err := updatePublicationAssets(base, p.Id, func(as *AssetMetadata) { as.Publication = p.Name })
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &p, nil
+}
+
+// DeletePublication removes a publication from the registry. Its assets
+// keep its name.
+func (a *App) DeletePublication(id string) error {
+	base, err := a.workspaceDir()
+	if err != nil {
+		return err
+	}
+	ps, err := readPublications(base)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(ps, func(v Publication) bool { return v.Id == id })
+	if i == -1 {
+		return fmt.Errorf("publication %s not found", id)
+	}
+	if err := writePublications(base, slices.Delete(ps, i, i+1)); err != nil {
+		return err
+	}
+	return updatePublicationAssets(base, id, func(as *AssetMetadata) { as.PublicationId = "" })
+}
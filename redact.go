@@ -0,0 +1,153 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"slices"
+)
+
+// Redactions hide personal data in exported reports. They are burned into
+// the pixels before the image is embedded, so nothing under them reaches
+// the PDF, while the images in the workspace stay untouched. Regions are
+// normalized to the image after its edits.
+
+type RedactMode string
+
+const (
+	REDACT_FILL     RedactMode = "fill"
+	REDACT_PIXELATE RedactMode = "pixelate"
+)
+
+type Redaction struct {
+	Region Region     `json:"region"`
+	Mode   RedactMode `json:"mode"`
+}
+
+// pixelBlocks is how many blocks the longest side of a pixelated region
+// gets, few enough that faces and digits can't be made out.
+const pixelBlocks = 6
+
+func (r Redaction) validate() error {
+	if r.Mode != REDACT_FILL && r.Mode != REDACT_PIXELATE {
+		return fmt.Errorf("unknown redaction mode %q", r.Mode)
+	}
+	if !r.Region.normalized() {
+		return errors.New("redaction must be non-empty and inside the image")
+	}
+	return nil
+}
+
+// redact returns a copy of img with the redactions burned in.
+func redact(img image.Image, rs []Redaction) image.Image {
+	if len(rs) == 0 {
+		return img
+	}
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
+
+	w, h := float64(b.Dx()), float64(b.Dy())
+	for _, r := range rs {
+		// round outwards so a region never leaves a sliver uncovered
+		px := image.Rect(
+			int(r.Region.X*w), int(r.Region.Y*h),
+			int(math.Ceil((r.Region.X+r.Region.W)*w)), int(math.Ceil((r.Region.Y+r.Region.H)*h)),
+		).Intersect(dst.Bounds())
+		switch r.Mode {
+		case REDACT_FILL:
+			for y := px.Min.Y; y < px.Max.Y; y++ {
+				for x := px.Min.X; x < px.Max.X; x++ {
+					dst.SetRGBA(x, y, color.RGBA{0, 0, 0, 255})
+				}
+			}
+		case REDACT_PIXELATE:
+			pixelate(dst, px)
+		}
+	}
+	return dst
+}
+
+// pixelate replaces every block of r with its average color.
+func pixelate(img *image.RGBA, r image.Rectangle) {
+	block := max(4, max(r.Dx(), r.Dy())/pixelBlocks)
+	for by := r.Min.Y; by < r.Max.Y; by += block {
+		for bx := r.Min.X; bx < r.Max.X; bx += block {
+			cell := image.Rect(bx, by, bx+block, by+block).Intersect(r)
+			var sr, sg, sb, sa, n int
+			for y := cell.Min.Y; y < cell.Max.Y; y++ {
+				for x := cell.Min.X; x < cell.Max.X; x++ {
+					c := img.RGBAAt(x, y)
+					sr, sg, sb, sa = sr+int(c.R), sg+int(c.G), sb+int(c.B), sa+int(c.A)
+					n++
+				}
+			}
+			if n == 0 {
+				continue
+			}
+			avg := color.RGBA{uint8(sr / n), uint8(sg / n), uint8(sb / n), uint8(sa / n)}
+			for y := cell.Min.Y; y < cell.Max.Y; y++ {
+				for x := cell.Min.X; x < cell.Max.X; x++ {
+					img.SetRGBA(x, y, avg)
+				}
+			}
+		}
+	}
+}
+
+// SetRedactions replaces the redactions of the sheet or cutout of an asset.
+func (a *App) SetRedactions(projectId string, assetId string, photo PhotoType, redactions []Redaction) error {
+	if projectId == "" || assetId == "" {
+		return fmt.Errorf("required project or asset ID not found")
+	}
+	for i, r := range redactions {
+		if err := r.validate(); err != nil {
+			return fmt.Errorf("redaction %d: %w", i+1, err)
+		}
+	}
+
+	base, err := a.workspaceDir()
+	if err != nil {
+		return err
+	}
+	proj, err := readProject(base, projectId)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(proj.Assets, func(as AssetMetadata) bool { return as.ID == assetId })
+	if i == -1 {
+		return fmt.Errorf("asset with ID %s not found in project", assetId)
+	}
+	as := &proj.Assets[i]
+	switch photo {
+	case SHEET:
+		as.SheetRedactions = redactions
+	case CUTOUT:
+		as.CutoutRedactions = redactions
+	default:
+		return fmt.Errorf("unknown photo type %q", photo)
+	}
+	as.UpdatedAt = timestamp()
+	return writeProject(base, proj)
+}
+
+// SetRedactedExport turns the redactions of a project's assets on or off
+// in its exported reports.
+func (a *App) SetRedactedExport(projectId string, on bool) error {
+	if projectId == "" {
+		return errors.New("invalid project ID")
+	}
+	base, err := a.workspaceDir()
+	if err != nil {
+		return err
+	}
+	proj, err := readProject(base, projectId)
+	if err != nil {
+		return err
+	}
+	proj.RedactedExport = on
+	return writeProject(base, proj)
+}
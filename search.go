@@ -0,0 +1,322 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// The search index lives in projects/search.json next to the summary index
+// and is refreshed by the same writes. It keeps the folded words of every
+// project and asset, never the images, so searching stays cheap.
+
+type searchAsset struct {
+	Id          string    `json:"id"`
+	Section     string    `json:"section"`
+	PageNumber  string    `json:"pageNumber"`
+	Publication string    `json:"publication"`
+	Headline    string    `json:"headline"`
+	MediaType   MediaType `json:"mediaType"`
+	EditionDate string    `json:"editionDate"`
+	CreatedAt   string    `json:"createdAt"`
+	Terms       []string  `json:"terms"`
+}
+
+// searchVersion is bumped when documents gain fields, so older indexes are
+// rebuilt.
+const searchVersion = 2
+
+type searchDoc struct {
+	Version     int           `json:"version"`
+	ProjectId   string        `json:"projectId"`
+	ProjectName string        `json:"projectName"`
+	Client      string        `json:"client"`
+	Status      ProjectStatus `json:"status"`
+	Terms       []string      `json:"terms"`
+	Assets      []searchAsset `json:"assets"`
+}
+
+// SearchFilters narrow a search. Zero values match everything. From/To
+// bound the asset date as YYYY-MM-DD, its edition date when known and its
+// upload date otherwise, and a zero Limit returns every hit.
+type SearchFilters struct {
+	ProjectId   string        `json:"projectId"`
+	Client      string        `json:"client"`
+	Status      ProjectStatus `json:"status"`
+	Section     string        `json:"section"`
+	Publication string        `json:"publication"`
+	MediaType   MediaType     `json:"mediaType"`
+	From        string        `json:"from"`
+	To          string        `json:"to"`
+	Limit       int           `json:"limit"`
+}
+
+// SearchResult is a matching asset with the project it belongs to.
+type SearchResult struct {
+	ProjectId   string        `json:"projectId"`
+	ProjectName string        `json:"projectName"`
+	Client      string        `json:"client"`
+	Status      ProjectStatus `json:"status"`
+	AssetId     string        `json:"assetId"`
+	Section     string        `json:"section"`
+	PageNumber  string        `json:"pageNumber"`
+	Publication string        `json:"publication"`
+	Headline    string        `json:"headline"`
+	MediaType   MediaType     `json:"mediaType"`
+	EditionDate string        `json:"editionDate"`
+	CreatedAt   string        `json:"createdAt"`
+}
+
+// searchMu serializes read-modify-write cycles on projects/search.json.
+var searchMu sync.Mutex
+
+func searchIndexFile(base string) string {
+	return filepath.Join(base, "projects", "search.json")
+}
+
+// fold lowercases s and strips its accents, so "Sección" and "seccion"
+// index the same way.
+func fold(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// tokenize splits the folded text of every field into unique words.
+func tokenize(fields ...string) []string {
+	var terms []string
+	for _, f := range fields {
+		for _, w := range strings.FieldsFunc(fold(f), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			if !slices.Contains(terms, w) {
+				terms = append(terms, w)
+			}
+		}
+	}
+	return terms
+}
+
+func projectTerms(p *Project) []string {
+	fields := []string{p.Name, p.Client, p.Description}
+	fields = append(fields, p.Tags...)
+	return tokenize(fields...)
+}
+
+func assetTerms(as AssetMetadata) []string {
+	fields := []string{as.Section, as.PageNumber, as.Publication, as.Headline, as.Byline, string(as.MediaType), as.URL}
+	for _, c := range as.Continuations {
+		fields = append(fields, c.PageNumber)
+	}
+	return tokenize(fields...)
+}
+
+func searchDocument(p *Project) searchDoc {
+	d := searchDoc{
+		Version:     searchVersion,
+		ProjectId:   p.Id,
+		ProjectName: p.Name,
+		Client:      p.Client,
+		Status:      p.Status,
+		Terms:       projectTerms(p),
+		Assets:      make([]searchAsset, 0, len(p.Assets)),
+	}
+	for _, as := range p.Assets {
+		d.Assets = append(d.Assets, searchAsset{
+			Id:          as.ID,
+			Section:     as.Section,
+			PageNumber:  as.PageNumber,
+			Publication: as.Publication,
+			Headline:    as.Headline,
+			MediaType:   as.MediaType,
+			EditionDate: as.EditionDate,
+			CreatedAt:   as.CreatedAt,
+			Terms:       assetTerms(as),
+		})
+	}
+	return d
+}
+
+func readSearchIndex(base string) (map[string]searchDoc, error) {
+	b, err := os.ReadFile(searchIndexFile(base))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return rebuildSearchIndex(base)
+		}
+		return nil, err
+	}
+	var idx map[string]searchDoc
+	if err := json.Unmarshal(b, &idx); err != nil || idx == nil {
+		return rebuildSearchIndex(base)
+	}
+	for _, d := range idx {
+		if d.Version != searchVersion {
+			return rebuildSearchIndex(base)
+		}
+	}
+	return idx, nil
+}
+
+// rebuildSearchIndex reads every project, for workspaces created before
+// the search index existed or whose index got corrupted.
+func rebuildSearchIndex(base string) (map[string]searchDoc, error) {
+	projectsPath := filepath.Join(base, "projects")
+	if err := os.MkdirAll(projectsPath, 0755); err != nil {
+		return nil, err
+	}
+	dirs, err := os.ReadDir(projectsPath)
+	if err != nil {
+		return nil, err
+	}
+	idx := make(map[string]searchDoc)
+	for _, v := range cleanupDirs(dirs) {
+		p, err := readProject(base, strings.TrimPrefix(v.Name(), "project-"))
+		if err != nil {
+			continue
+		}
+		idx[p.Id] = searchDocument(p)
+	}
+	return idx, writeJSON(searchIndexFile(base), idx)
+}
+
+func updateSearchIndex(base string, p *Project) error {
+	searchMu.Lock()
+	defer searchMu.Unlock()
+	idx, err := readSearchIndex(base)
+	if err != nil {
+		return err
+	}
+	idx[p.Id] = searchDocument(p)
+	return writeJSON(searchIndexFile(base), idx)
+}
+
+func removeFromSearchIndex(base string, id string) error {
+	searchMu.Lock()
+	defer searchMu.Unlock()
+	idx, err := readSearchIndex(base)
+	if err != nil {
+		return err
+	}
+	delete(idx, id)
+	return writeJSON(searchIndexFile(base), idx)
+}
+
+// indexProject refreshes every index that holds p.
+func indexProject(base string, p *Project) error {
+	if err := updateSummary(base, summarize(p)); err != nil {
+		return err
+	}
+	return updateSearchIndex(base, p)
+}
+
+func unindexProject(base string, id string) error {
+	if err := removeSummary(base, id); err != nil {
+		return err
+	}
+	return removeFromSearchIndex(base, id)
+}
+
+// hasPrefix reports whether some term starts with word.
+func hasPrefix(terms []string, word string) bool {
+	return slices.ContainsFunc(terms, func(t string) bool { return strings.HasPrefix(t, word) })
+}
+
+// Search returns the assets matching every word of query, newest first.
+// Words match by prefix, ignoring case and accents, against the asset and
+// its project, so "deport 14" finds page 14 of Sección Deportes. An empty
+// query lists every asset the filters allow.
+func (a *App) Search(query string, filters SearchFilters) ([]SearchResult, error) {
+	base, err := a.workspaceDir()
+	if err != nil {
+		return nil, err
+	}
+	searchMu.Lock()
+	idx, err := readSearchIndex(base)
+	searchMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	words := tokenize(query)
+	section := fold(strings.TrimSpace(filters.Section))
+	publication := fold(strings.TrimSpace(filters.Publication))
+	res := []SearchResult{}
+	for _, d := range idx {
+		if filters.ProjectId != "" && filters.ProjectId != d.ProjectId {
+			continue
+		}
+		if filters.Client != "" && !strings.EqualFold(strings.TrimSpace(filters.Client), d.Client) {
+			continue
+		}
+		if filters.Status != "" && filters.Status != d.Status {
+			continue
+		}
+		for _, as := range d.Assets {
+			if section != "" && fold(as.Section) != section {
+				continue
+			}
+			if publication != "" && fold(as.Publication) != publication {
+				continue
+			}
+			if filters.MediaType != "" && filters.MediaType != as.MediaType {
+				continue
+			}
+			// asset dates are RFC 3339, their first ten characters compare as dates
+			day := as.CreatedAt[:min(len(as.CreatedAt), 10)]
+			if as.EditionDate != "" {
+				day = as.EditionDate
+			}
+			if filters.From != "" && day < filters.From || filters.To != "" && day > filters.To {
+				continue
+			}
+			matched := true
+			for _, w := range words {
+				if !hasPrefix(as.Terms, w) && !hasPrefix(d.Terms, w) {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				res = append(res, SearchResult{
+					ProjectId:   d.ProjectId,
+					ProjectName: d.ProjectName,
+					Client:      d.Client,
+					Status:      d.Status,
+					AssetId:     as.Id,
+					Section:     as.Section,
+					PageNumber:  as.PageNumber,
+					Publication: as.Publication,
+					Headline:    as.Headline,
+					MediaType:   as.MediaType,
+					EditionDate: as.EditionDate,
+					CreatedAt:   as.CreatedAt,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		ti, tj := sortableTime(res[i].CreatedAt), sortableTime(res[j].CreatedAt)
+		if ti == tj {
+			return res[i].AssetId < res[j].AssetId
+		}
+		return ti > tj
+	})
+	if filters.Limit > 0 && len(res) > filters.Limit {
+		res = res[:filters.Limit]
+	}
+	return res, nil
+}
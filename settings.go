@@ -0,0 +1,302 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/signintech/gopdf"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Settings are the app wide preferences, stored in
+// <config dir>/hyuga/settings.json next to (not inside) the workspaces.
+// Bump settingsVersion and extend migrateSettings when the shape changes.
+type Settings struct {
+	Version            int            `json:"version"`
+	ExportDir          string         `json:"exportDir"`
+	PageSize           string         `json:"pageSize"`
+	Margin             float64        `json:"margin"` // points
+	Language           string         `json:"language"`
+	ImageQuality       int            `json:"imageQuality"` // JPEG quality, 1-100
+	MaxImageSide       int            `json:"maxImageSide"` // uploads are downscaled to this many pixels, 0 keeps them as is
+	LastModel          string         `json:"lastModel"`    // machote of the last upload, the default when nothing else picks one
+	WindowWidth        int            `json:"windowWidth"`
+	WindowHeight       int            `json:"windowHeight"`
+	TrashRetentionDays int            `json:"trashRetentionDays"` // 0 keeps trash until emptied
+	Backup             BackupSettings `json:"backup"`
+	ActiveWorkspace    string         `json:"activeWorkspace"`
+	Workspaces         []Workspace    `json:"workspaces"`
+}
+
+const (
+	settingsVersion      = 2
+	settingsChangedEvent = "settings:changed"
+)
+
+var pageSizes = map[string]*gopdf.Rect{
+	"A4":     gopdf.PageSizeA4,
+	"A3":     gopdf.PageSizeA3,
+	"Letter": gopdf.PageSizeLetter,
+	"Legal":  gopdf.PageSizeLegal,
+}
+
+var languages = []string{"es", "en"}
+
+// settingsMu serializes read-modify-write cycles on settings.json.
+var settingsMu sync.Mutex
+
+func settingsFile(cfg string) string {
+	return filepath.Join(cfg, "settings.json")
+}
+
+func defaultSettings(cfg string) Settings {
+	exportDir := ""
+	if home, err := os.UserHomeDir(); err == nil {
+		exportDir = filepath.Join(home, "Downloads")
+	}
+	return Settings{
+		Version:            settingsVersion,
+		ExportDir:          exportDir,
+		PageSize:           "A4",
+		Margin:             36, // 0.5 inch
+		Language:           "es",
+		ImageQuality:       85,
+		MaxImageSide:       defaultMaxImageSide,
+		WindowWidth:        1024,
+		WindowHeight:       768,
+		TrashRetentionDays: defaultTrashRetentionDays,
+		Backup:             BackupSettings{Keep: defaultBackupKeep},
+		ActiveWorkspace:    defaultWorkspaceId,
+		Workspaces:         []Workspace{{Id: defaultWorkspaceId, Name: "Principal", Path: cfg}},
+	}
+}
+
+func loadSettings() (*Settings, error) {
+	cfg, err := getBaseConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	var s Settings
+	if err := readJSON(settingsFile(cfg), &s); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("invalid settings file: %w", err)
+		}
+	}
+	if s.Version < settingsVersion {
+		if err := migrateSettings(cfg, &s); err != nil {
+			return nil, err
+		}
+		if err := writeJSON(settingsFile(cfg), s); err != nil {
+			return nil, err
+		}
+	}
+	return &s, nil
+}
+
+// migrateSettings upgrades s to settingsVersion. Version 0 means no
+// settings.json yet. Version 2 added upload downscaling.
+func migrateSettings(cfg string, s *Settings) error {
+	if s.Version == 0 {
+		*s = defaultSettings(cfg)
+	}
+	if s.Version < 2 {
+		s.MaxImageSide = defaultMaxImageSide
+	}
+	s.Version = settingsVersion
+	return nil
+}
+
+// updateSettings applies fn to the stored settings and saves the result.
+func updateSettings(fn func(s *Settings) error) (*Settings, error) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	s, err := loadSettings()
+	if err != nil {
+		return nil, err
+	}
+	if err := fn(s); err != nil {
+		return nil, err
+	}
+	cfg, err := getBaseConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	for i := range s.Workspaces {
+		s.Workspaces[i].Active = false
+	}
+	return s, writeJSON(settingsFile(cfg), s)
+}
+
+func (s Settings) validate() error {
+	if _, ok := pageSizes[s.PageSize]; !ok {
+		return fmt.Errorf("unsupported page size %q", s.PageSize)
+	}
+	page := pageSizes[s.PageSize]
+	if s.Margin < 0 || 2*s.Margin >= min(page.W, page.H)/2 {
+		return fmt.Errorf("margin %v does not fit the page", s.Margin)
+	}
+	if !slices.Contains(languages, s.Language) {
+		return fmt.Errorf("unsupported language %q", s.Language)
+	}
+	if s.ImageQuality < 1 || s.ImageQuality > 100 {
+		return errors.New("image quality must be between 1 and 100")
+	}
+	if s.MaxImageSide < 0 || s.MaxImageSide > maxImageSide {
+		return fmt.Errorf("max image side must be between 0 and %d", maxImageSide)
+	}
+	if s.WindowWidth < 800 || s.WindowHeight < 600 {
+		return errors.New("window must be at least 800x600")
+	}
+	if s.TrashRetentionDays < 0 {
+		return errors.New("retention days cannot be negative")
+	}
+	if s.Backup.IntervalHours < 0 {
+		return errors.New("backup interval cannot be negative")
+	}
+	if s.Backup.Keep < 1 {
+		return errors.New("at least one backup must be kept")
+	}
+	if s.Backup.IntervalHours > 0 && s.Backup.Dir == "" {
+		return errors.New("scheduled backups need a backup folder")
+	}
+	return nil
+}
+
+func currentSettings() (*Settings, error) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	return loadSettings()
+}
+
+func (a *App) GetSettings() (*Settings, error) {
+	return currentSettings()
+}
+
+// UpdateSettings validates and stores the preferences in upd and emits
+// settings:changed with the result. Workspaces are managed by their own
+// methods, and the last machote and window size are kept by the app
+// itself, so those are ignored here.
+func (a *App) UpdateSettings(upd Settings) (*Settings, error) {
+	upd.ExportDir = strings.TrimSpace(upd.ExportDir)
+	var prevBackup BackupSettings
+	s, err := updateSettings(func(s *Settings) error {
+		prevBackup = s.Backup
+		upd.Version = s.Version
+		upd.ActiveWorkspace = s.ActiveWorkspace
+		upd.Workspaces = s.Workspaces
+		upd.LastModel = s.LastModel
+		upd.WindowWidth = s.WindowWidth
+		upd.WindowHeight = s.WindowHeight
+		if err := upd.validate(); err != nil {
+			return err
+		}
+		*s = upd
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if s.Backup != prevBackup {
+		a.scheduleBackups(s.Backup)
+	}
+	a.emitSettings(s)
+	return s, nil
+}
+
+func (a *App) emitSettings(s *Settings) {
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, settingsChangedEvent, s)
+	}
+}
+
+// pageSetup is the page geometry every PDF page is laid out with.
+type pageSetup struct {
+	W, H    float64
+	Margin  float64
+	Quality int
+	Lang    string
+}
+
+func (s Settings) pageSetup() pageSetup {
+	page, ok := pageSizes[s.PageSize]
+	if !ok {
+		page = gopdf.PageSizeA4
+	}
+	return pageSetup{W: page.W, H: page.H, Margin: s.Margin, Quality: s.ImageQuality, Lang: s.Language}
+}
+
+var messages = map[string]map[string]string{
+	"es": {
+		"client":         "Cliente",
+		"period":         "Periodo",
+		"status":         "Estado",
+		"tags":           "Etiquetas",
+		"placeholder":    "Selecciona machote",
+		"continued_on":   "continúa en pág. %s",
+		"continued_from": "viene de pág. %s",
+		"page":           "pág. %s",
+		"yes":            "sí",
+		"no":             "no",
+		"byline":         "por %s",
+		"date":           "%d de %s de %d",
+		"january":        "enero",
+		"february":       "febrero",
+		"march":          "marzo",
+		"april":          "abril",
+		"may":            "mayo",
+		"june":           "junio",
+		"july":           "julio",
+		"august":         "agosto",
+		"september":      "septiembre",
+		"october":        "octubre",
+		"november":       "noviembre",
+		"december":       "diciembre",
+		"draft":          "Borrador",
+		"in_review":      "En revisión",
+		"delivered":      "Entregado",
+		"archived":       "Archivado",
+	},
+	"en": {
+		"client":         "Client",
+		"period":         "Period",
+		"status":         "Status",
+		"tags":           "Tags",
+		"placeholder":    "Select template",
+		"continued_on":   "continued on page %s",
+		"continued_from": "continued from page %s",
+		"page":           "p. %s",
+		"yes":            "yes",
+		"no":             "no",
+		"byline":         "by %s",
+		"date":           "%[2]s %[1]d, %[3]d",
+		"january":        "January",
+		"february":       "February",
+		"march":          "March",
+		"april":          "April",
+		"may":            "May",
+		"june":           "June",
+		"july":           "July",
+		"august":         "August",
+		"september":      "September",
+		"october":        "October",
+		"november":       "November",
+		"december":       "December",
+		"draft":          "Draft",
+		"in_review":      "In review",
+		"delivered":      "Delivered",
+		"archived":       "Archived",
+	},
+}
+
+// tr returns the message for key in lang, falling back to Spanish.
+func tr(lang, key string) string {
+	if m, ok := messages[lang][key]; ok {
+		return m
+	}
+	return messages["es"][key]
+}
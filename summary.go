@@ -0,0 +1,246 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ProjectSummary is the slice of a project the home screen needs. Summaries
+// live in projects/index.json and are refreshed on every project write, so
+// listing projects never has to unmarshal the base64 images.
+type ProjectSummary struct {
+	Id            string        `json:"id"`
+	Name          string        `json:"name"`
+	CreatedAt     string        `json:"created_at"`
+	UpdatedAt     string        `json:"updated_at"`
+	Client        string        `json:"client"`
+	Status        ProjectStatus `json:"status"`
+	Tags          []string      `json:"tags"`
+	PeriodStart   string        `json:"period_start"`
+	PeriodEnd     string        `json:"period_end"`
+	AssetCount    int           `json:"asset_count"`
+	ThumbnailHash string        `json:"thumbnail_hash"`
+}
+
+// ProjectQuery filters, sorts and paginates project listings. Zero values
+// match everything and a zero Limit returns every match. From/To select
+// projects whose period overlaps the range.
+type ProjectQuery struct {
+	Client string        `json:"client"`
+	Status ProjectStatus `json:"status"`
+	Tag    string        `json:"tag"`
+	From   string        `json:"from"`
+	To     string        `json:"to"`
+	SortBy string        `json:"sortBy"`
+	Desc   bool          `json:"desc"`
+	Offset int           `json:"offset"`
+	Limit  int           `json:"limit"`
+}
+
+type SummaryPage struct {
+	Items []ProjectSummary `json:"items"`
+	Total int              `json:"total"`
+}
+
+// indexMu serializes read-modify-write cycles on projects/index.json.
+var indexMu sync.Mutex
+
+func summaryIndexFile(base string) string {
+	return filepath.Join(base, "projects", "index.json")
+}
+
+func summarize(p *Project) ProjectSummary {
+	s := ProjectSummary{
+		Id:          p.Id,
+		Name:        p.Name,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+		Client:      p.Client,
+		Status:      p.Status,
+		Tags:        p.Tags,
+		PeriodStart: p.PeriodStart,
+		PeriodEnd:   p.PeriodEnd,
+		AssetCount:  len(p.Assets),
+	}
+	if len(p.Assets) > 0 {
+		// a broken first image only costs the thumbnail
+		ref := p.Assets[0].Cutout
+		if ref == "" {
+			ref = p.Assets[0].Sheet
+		}
+		s.ThumbnailHash, _ = imageHash(ref)
+	}
+	return s
+}
+
+func readSummaries(base string) (map[string]ProjectSummary, error) {
+	b, err := os.ReadFile(summaryIndexFile(base))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return rebuildSummaries(base)
+		}
+		return nil, err
+	}
+	var idx map[string]ProjectSummary
+	if err := json.Unmarshal(b, &idx); err != nil || idx == nil {
+		return rebuildSummaries(base)
+	}
+	return idx, nil
+}
+
+// rebuildSummaries scans every project folder, the one time full read
+// for workspaces created before the index existed.
+func rebuildSummaries(base string) (map[string]ProjectSummary, error) {
+	projectsPath := filepath.Join(base, "projects")
+	if err := os.MkdirAll(projectsPath, 0755); err != nil {
+		return nil, err
+	}
+	dirs, err := os.ReadDir(projectsPath)
+	if err != nil {
+		return nil, err
+	}
+	idx := make(map[string]ProjectSummary)
+	for _, v := range cleanupDirs(dirs) {
+		p, err := readProject(base, strings.TrimPrefix(v.Name(), "project-"))
+		if err != nil {
+			continue
+		}
+		idx[p.Id] = summarize(p)
+	}
+	return idx, writeSummaries(base, idx)
+}
+
+func writeSummaries(base string, idx map[string]ProjectSummary) error {
+	return writeJSON(summaryIndexFile(base), idx)
+}
+
+func updateSummary(base string, s ProjectSummary) error {
+	indexMu.Lock()
+	defer indexMu.Unlock()
+	idx, err := readSummaries(base)
+	if err != nil {
+		return err
+	}
+	idx[s.Id] = s
+	return writeSummaries(base, idx)
+}
+
+func removeSummary(base string, id string) error {
+	indexMu.Lock()
+	defer indexMu.Unlock()
+	idx, err := readSummaries(base)
+	if err != nil {
+		return err
+	}
+	delete(idx, id)
+	return writeSummaries(base, idx)
+}
+
+// LoadProjectSummaries lists projects from the summary index only.
+func (a *App) LoadProjectSummaries(q ProjectQuery) (*SummaryPage, error) {
+	base, err := a.workspaceDir()
+	if err != nil {
+		return nil, err
+	}
+	indexMu.Lock()
+	idx, err := readSummaries(base)
+	indexMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]ProjectSummary, 0, len(idx))
+	for _, s := range idx {
+		if q.matches(s) {
+			items = append(items, s)
+		}
+	}
+	sortSummaries(items, q.SortBy, q.Desc)
+
+	page := &SummaryPage{Total: len(items)}
+	start := min(max(q.Offset, 0), len(items))
+	end := len(items)
+	if q.Limit > 0 {
+		end = min(start+q.Limit, len(items))
+	}
+	page.Items = items[start:end]
+	return page, nil
+}
+
+// GetProjectSummary returns the summary of one project, for views that
+// show its assets as thumbnails.
+func (a *App) GetProjectSummary(projectId string) (*ProjectSummary, error) {
+	base, err := a.workspaceDir()
+	if err != nil {
+		return nil, err
+	}
+	indexMu.Lock()
+	idx, err := readSummaries(base)
+	indexMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	s, ok := idx[projectId]
+	if !ok {
+		return nil, fmt.Errorf("project %s not found", projectId)
+	}
+	return &s, nil
+}
+
+func (q ProjectQuery) matches(s ProjectSummary) bool {
+	if q.Client != "" && !strings.EqualFold(strings.TrimSpace(q.Client), s.Client) {
+		return false
+	}
+	if q.Status != "" && q.Status != s.Status {
+		return false
+	}
+	if q.Tag != "" && !slices.ContainsFunc(s.Tags, func(t string) bool { return strings.EqualFold(t, q.Tag) }) {
+		return false
+	}
+	// dates are stored as YYYY-MM-DD so they compare lexically
+	if q.From != "" && s.PeriodEnd != "" && s.PeriodEnd < q.From {
+		return false
+	}
+	if q.To != "" && s.PeriodStart != "" && s.PeriodStart > q.To {
+		return false
+	}
+	return true
+}
+
+func sortSummaries(ss []ProjectSummary, by string, desc bool) {
+	key := func(s ProjectSummary) string {
+		switch by {
+		case "name":
+			return strings.ToLower(s.Name)
+		case "client":
+			return strings.ToLower(s.Client)
+		case "period_start":
+			return s.PeriodStart
+		case "period_end":
+			return s.PeriodEnd
+		case "status":
+			return string(s.Status)
+		case "updated_at":
+			return sortableTime(s.UpdatedAt)
+		default:
+			return sortableTime(s.CreatedAt)
+		}
+	}
+	sort.SliceStable(ss, func(i, j int) bool {
+		ki, kj := key(ss[i]), key(ss[j])
+		if ki == kj {
+			return ss[i].Id < ss[j].Id
+		}
+		if desc {
+			return ki > kj
+		}
+		return ki < kj
+	})
+}
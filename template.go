@@ -0,0 +1,157 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ProjectTemplate pre-fills new projects, e.g. for recurring weekly reports
+// of the same client.
+type ProjectTemplate struct {
+	Id           string          `json:"id"`
+	Name         string          `json:"name"`
+	Metadata     ProjectMetadata `json:"metadata"`
+	Layout       ProjectLayout   `json:"layout"`
+	DefaultModel string          `json:"defaultModel"`
+	Fields       []CustomField   `json:"fields"`
+}
+
+func templatesFile(base string) string {
+	return filepath.Join(base, "templates", "templates.json")
+}
+
+func readTemplates(base string) ([]ProjectTemplate, error) {
+	var ts []ProjectTemplate
+	if err := readJSON(templatesFile(base), &ts); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []ProjectTemplate{}, nil
+		}
+		return nil, fmt.Errorf("invalid templates file: %w", err)
+	}
+	return ts, nil
+}
+
+func writeTemplates(base string, ts []ProjectTemplate) error {
+	if err := os.MkdirAll(filepath.Dir(templatesFile(base)), 0755); err != nil {
+		return err
+	}
+	return writeJSON(templatesFile(base), ts)
+}
+
+func findTemplate(base, id string) (*ProjectTemplate, error) {
+	ts, err := readTemplates(base)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range ts {
+		if t.Id == id {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("template %s not found", id)
+}
+
+func (a *App) LoadTemplates() ([]ProjectTemplate, error) {
+	base, err := a.workspaceDir()
+	if err != nil {
+		return nil, err
+	}
+	return readTemplates(base)
+}
+
+// SaveTemplate creates the template when it has no ID and replaces the
+// stored one otherwise.
+func (a *App) SaveTemplate(t ProjectTemplate) (*ProjectTemplate, error) {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" {
+		return nil, errors.New("template name is required")
+	}
+	if t.Metadata.Status == "" {
+		t.Metadata.Status = DRAFT
+	}
+	if err := t.Metadata.validate(); err != nil {
+		return nil, err
+	}
+	if t.Layout.ModelRatio <= 0 || t.Layout.ModelRatio >= 1 {
+		t.Layout = defaultLayout()
+	}
+	t.Metadata.Tags = cleanTags(t.Metadata.Tags)
+	if err := cleanFieldSchema(t.Fields); err != nil {
+		return nil, err
+	}
+	if !noModel(t.DefaultModel) {
+		if err := checkImageFile("model", t.DefaultModel); err != nil {
+			return nil, err
+		}
+	}
+
+	base, err := a.workspaceDir()
+	if err != nil {
+		return nil, err
+	}
+	ts, err := readTemplates(base)
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(ts, func(v ProjectTemplate) bool { return v.Id == t.Id })
+	switch {
+	case t.Id == "":
+		t.Id = uuid.NewString()
+		ts = append(ts, t)
+	case i == -1:
+		return nil, fmt.Errorf("template %s not found", t.Id)
+	default:
+		ts[i] = t
+	}
+	if err := writeTemplates(base, ts); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// SaveProjectAsTemplate stores the metadata, layout and default machote of
+// an existing project as a new template.
+func (a *App) SaveProjectAsTemplate(projectId string, name string) (*ProjectTemplate, error) {
+	base, err := a.workspaceDir()
+	if err != nil {
+		return nil, err
+	}
+	p, err := readProject(base, projectId)
+	if err != nil {
+		return nil, err
+	}
+	return a.SaveTemplate(ProjectTemplate{
+		Name: name,
+		Metadata: ProjectMetadata{
+			Client:      p.Client,
+			Description: p.Description,
+			Status:      DRAFT,
+			Tags:        p.Tags,
+		},
+		Layout:       p.Layout,
+		DefaultModel: p.DefaultModel,
+		Fields:       p.Fields,
+	})
+}
+
+func (a *App) DeleteTemplate(id string) error {
+	base, err := a.workspaceDir()
+	if err != nil {
+		return err
+	}
+	ts, err := readTemplates(base)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(ts, func(v ProjectTemplate) bool { return v.Id == id })
+	if i == -1 {
+		return fmt.Errorf("template %s not found", id)
+	}
+	return writeTemplates(base, slices.Delete(ts, i, i+1))
+}
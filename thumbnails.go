@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Thumbnails let lists and previews show images without shipping the full
+// base64 payloads over the bridge. They are cached under cache/thumbs by
+// the hash of the source image, made at upload for new images and on
+// first request for older ones.
+
+type ThumbnailSize string
+
+const (
+	THUMB_SMALL  ThumbnailSize = "small"  // lists and grids
+	THUMB_MEDIUM ThumbnailSize = "medium" // cards and pickers
+	THUMB_LARGE  ThumbnailSize = "large"  // previews
+)
+
+type thumbnailSide struct {
+	size ThumbnailSize
+	side int
+}
+
+// thumbnailSides is the longest side of every size, largest first so each
+// can be scaled from the previous one.
+var thumbnailSides = []thumbnailSide{
+	{THUMB_LARGE, 1200},
+	{THUMB_MEDIUM, 480},
+	{THUMB_SMALL, 160},
+}
+
+const thumbnailQuality = 80
+
+func thumbnailFile(base, hash string, size ThumbnailSize) string {
+	return filepath.Join(base, "cache", "thumbs", fmt.Sprintf("%s-%s.jpg", hash, size))
+}
+
+// makeThumbnails writes every size of the image ref to the cache of base
+// and returns its hash.
+func makeThumbnails(base, ref string) (string, error) {
+	hash, err := imageHash(ref)
+	if err != nil {
+		return "", err
+	}
+	img, err := decodeImage(ref)
+	if err != nil {
+		return "", err
+	}
+	// thumbnails are JPEG, so transparency is flattened onto paper
+	b := img.Bounds()
+	flat := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(paper), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), img, b.Min, draw.Over)
+	img = flat
+	for _, t := range thumbnailSides {
+		img = downscale(img, t.side)
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+			return "", err
+		}
+		if err := writeCacheFile(thumbnailFile(base, hash, t.size), buf.Bytes()); err != nil {
+			return "", err
+		}
+	}
+	return hash, nil
+}
+
+// cacheThumbnails makes the thumbnails of refs ahead of time. Failures
+// are only logged, GetThumbnail will try again.
+func cacheThumbnails(base string, refs ...string) {
+	for _, ref := range refs {
+		if ref == "" || ref == "empty" {
+			continue
+		}
+		if _, err := makeThumbnails(base, ref); err != nil {
+			fmt.Println(concat("could not make thumbnails: ", err.Error()))
+		}
+	}
+}
+
+// findImage looks through the models and projects of base for the image
+// with the given hash, for thumbnails of images stored before they
+// existed.
+func findImage(base, hash string) (string, error) {
+	models, _ := filepath.Glob(filepath.Join(base, "models", "images", hash+"*"))
+	if len(models) > 0 {
+		return models[0], nil
+	}
+	idx, err := readSummaries(base)
+	if err != nil {
+		return "", err
+	}
+	// the project the hash was listed for is the likely one
+	ids := make([]string, 0, len(idx))
+	for id, s := range idx {
+		if s.ThumbnailHash == hash {
+			ids = append([]string{id}, ids...)
+		} else {
+			ids = append(ids, id)
+		}
+	}
+	for _, id := range ids {
+		p, err := readProject(base, id)
+		if err != nil {
+			continue
+		}
+		for _, as := range p.Assets {
+			for _, ref := range as.images() {
+				if h, err := imageHash(ref); err == nil && h == hash {
+					return ref, nil
+				}
+			}
+		}
+	}
+	return "", fmt.Errorf("image %s not found", hash)
+}
+
+func isImageHash(s string) bool {
+	h, ok := hashFromSavedPath(s)
+	return ok && h == s
+}
+
+// GetThumbnail returns a thumbnail of an image as a data URL. ref is an
+// image hash, as in ProjectSummary and AssetSummary, or an image
+// reference: a data URL or the path of a model in the workspace.
+func (a *App) GetThumbnail(ref string, size ThumbnailSize) (string, error) {
+	if !slices.ContainsFunc(thumbnailSides, func(t thumbnailSide) bool { return t.size == size }) {
+		return "", fmt.Errorf("unknown thumbnail size %q", size)
+	}
+	base, err := a.workspaceDir()
+	if err != nil {
+		return "", err
+	}
+	ref = strings.TrimSpace(ref)
+	if filepath.IsAbs(ref) {
+		rel, err := filepath.Rel(base, ref)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return "", errors.New("image is outside the workspace")
+		}
+	}
+
+	hash := ref
+	if !isImageHash(ref) {
+		if hash, err = imageHash(ref); err != nil {
+			return "", fmt.Errorf("invalid image: %w", err)
+		}
+	}
+	file := thumbnailFile(base, hash, size)
+	if b, err := os.ReadFile(file); err == nil {
+		return dataURL("image/jpeg", b), nil
+	}
+
+	if isImageHash(ref) {
+		if ref, err = findImage(base, hash); err != nil {
+			return "", err
+		}
+	}
+	if _, err := makeThumbnails(base, ref); err != nil {
+		return "", fmt.Errorf("cannot make thumbnail: %w", err)
+	}
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	return dataURL("image/jpeg", b), nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Timestamps are stored as RFC 3339 strings carrying the local offset, so
+// they sort and compare correctly across machines in other timezones.
+
+func timestamp() string {
+	return time.Now().Format(time.RFC3339)
+}
+
+// parseTimestamp reads RFC 3339 as well as the zone-less time.DateTime
+// format projects used to be created with, which is taken as local time.
+func parseTimestamp(s string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, true
+	}
+	if t, err := time.ParseInLocation(time.DateTime, s, time.Local); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// sortableTime maps a timestamp to a UTC string whose lexical order is its
+// chronological order. Unparseable values sort first.
+func sortableTime(s string) string {
+	t, ok := parseTimestamp(s)
+	if !ok {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// migrateTimestamps rewrites legacy timestamps of p in place and fills in
+// the updated/created fields older projects lack. It reports whether
+// anything changed.
+func migrateTimestamps(p *Project) bool {
+	changed := false
+	if t, ok := parseTimestamp(p.CreatedAt); ok {
+		if v := t.Format(time.RFC3339); v != p.CreatedAt {
+			p.CreatedAt = v
+			changed = true
+		}
+	}
+	if p.UpdatedAt == "" {
+		p.UpdatedAt = p.CreatedAt
+		changed = true
+	}
+	for i := range p.Assets {
+		as := &p.Assets[i]
+		if as.CreatedAt == "" {
+			as.CreatedAt = p.CreatedAt
+			changed = true
+		}
+		if as.UpdatedAt == "" {
+			as.UpdatedAt = as.CreatedAt
+			changed = true
+		}
+	}
+	return changed
+}
+
+// migrateProjects persists the timestamp migration for every project in
+// the workspace. readProject already migrates on the fly, this makes the
+// files themselves portable.
+func migrateProjects(base string) error {
+	dirs, err := os.ReadDir(filepath.Join(base, "projects"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, v := range cleanupDirs(dirs) {
+		id := strings.TrimPrefix(v.Name(), "project-")
+		var raw Project
+		if err := readJSON(filepath.Join(projectDir(base, id), "project.json"), &raw); err != nil {
+			fmt.Println(concat("could not read project ", id, ": ", err.Error()))
+			continue
+		}
+		if !migrateTimestamps(&raw) {
+			continue
+		}
+		p, err := readProject(base, id)
+		if err != nil {
+			continue
+		}
+		if err := storeProject(base, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TrashKind string
+
+const (
+	TRASH_PROJECT TrashKind = "project"
+	TRASH_ASSET   TrashKind = "asset"
+)
+
+// TrashEntry describes something deleted from the workspace. Each entry is
+// a folder under trash/ holding entry.json plus either the moved project
+// folder or the removed asset as asset.json.
+type TrashEntry struct {
+	Id          string    `json:"id"`
+	Kind        TrashKind `json:"kind"`
+	ProjectId   string    `json:"projectId"`
+	ProjectName string    `json:"projectName"`
+	AssetId     string    `json:"assetId"`
+	DeletedAt   string    `json:"deletedAt"`
+}
+
+const defaultTrashRetentionDays = 30
+
+func trashDir(base string) string {
+	return filepath.Join(base, "trash")
+}
+
+func newTrashEntry(base string, e TrashEntry) (string, error) {
+	e.Id = uuid.NewString()
+	e.DeletedAt = timestamp()
+	dir := filepath.Join(trashDir(base), e.Id)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	if err := writeJSON(filepath.Join(dir, "entry.json"), e); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return dir, nil
+}
+
+func trashProject(base, id, name string) error {
+	src := projectDir(base, id)
+	if _, err := os.Stat(src); err != nil {
+		return fmt.Errorf("cannot find project folder: %w", err)
+	}
+	dir, err := newTrashEntry(base, TrashEntry{Kind: TRASH_PROJECT, ProjectId: id, ProjectName: name})
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(src, filepath.Join(dir, filepath.Base(src))); err != nil {
+		os.RemoveAll(dir)
+		return fmt.Errorf("failed to move project to trash: %w", err)
+	}
+	return nil
+}
+
+func trashAsset(base string, p Project, as AssetMetadata) error {
+	dir, err := newTrashEntry(base, TrashEntry{Kind: TRASH_ASSET, ProjectId: p.Id, ProjectName: p.Name, AssetId: as.ID})
+	if err != nil {
+		return err
+	}
+	if err := writeJSON(filepath.Join(dir, "asset.json"), as); err != nil {
+		os.RemoveAll(dir)
+		return fmt.Errorf("failed to move asset to trash: %w", err)
+	}
+	return nil
+}
+
+func readTrash(base string) ([]TrashEntry, error) {
+	dirs, err := os.ReadDir(trashDir(base))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []TrashEntry{}, nil
+		}
+		return nil, err
+	}
+	res := []TrashEntry{}
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		var e TrashEntry
+		if err := readJSON(filepath.Join(trashDir(base), d.Name(), "entry.json"), &e); err != nil {
+			continue
+		}
+		res = append(res, e)
+	}
+	sort.Slice(res, func(i, j int) bool { return sortableTime(res[i].DeletedAt) > sortableTime(res[j].DeletedAt) })
+	return res, nil
+}
+
+// ListTrash returns the trashed projects and assets, newest first.
+func (a *App) ListTrash() ([]TrashEntry, error) {
+	base, err := a.workspaceDir()
+	if err != nil {
+		return nil, err
+	}
+	return readTrash(base)
+}
+
+func (a *App) RestoreFromTrash(entryId string) error {
+	if entryId == "" {
+		return errors.New("invalid trash entry ID")
+	}
+	base, err := a.workspaceDir()
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(trashDir(base), filepath.Base(entryId))
+	var e TrashEntry
+	if err := readJSON(filepath.Join(dir, "entry.json"), &e); err != nil {
+		return fmt.Errorf("cannot read trash entry: %w", err)
+	}
+
+	switch e.Kind {
+	case TRASH_PROJECT:
+		dst := projectDir(base, e.ProjectId)
+		if _, err := os.Stat(dst); err == nil {
+			return fmt.Errorf("project %s already exists", e.ProjectId)
+		}
+		if err := os.Rename(filepath.Join(dir, filepath.Base(dst)), dst); err != nil {
+			return fmt.Errorf("failed to restore project: %w", err)
+		}
+		p, err := readProject(base, e.ProjectId)
+		if err != nil {
+			return err
+		}
+		if err := indexProject(base, p); err != nil {
+			return err
+		}
+	case TRASH_ASSET:
+		p, err := readProject(base, e.ProjectId)
+		if err != nil {
+			return fmt.Errorf("project %q must be restored first: %w", e.ProjectName, err)
+		}
+		var as AssetMetadata
+		if err := readJSON(filepath.Join(dir, "asset.json"), &as); err != nil {
+			return fmt.Errorf("cannot read trashed asset: %w", err)
+		}
+		if !ensureUnique(*p, as) {
+			return fmt.Errorf("asset %s already exists in project", as.ID)
+		}
+		p.Assets = append(p.Assets, as)
+		if err := writeProject(base, p); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown trash entry kind %q", e.Kind)
+	}
+	return os.RemoveAll(dir)
+}
+
+func (a *App) EmptyTrash() error {
+	base, err := a.workspaceDir()
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(trashDir(base))
+}
+
+// purgeTrash drops entries older than retentionDays. Zero keeps them.
+func purgeTrash(base string, retentionDays int) error {
+	if retentionDays <= 0 {
+		return nil
+	}
+	entries, err := readTrash(base)
+	if err != nil {
+		return err
+	}
+	cutoff := time.Now().AddDate(0, 0, -retentionDays)
+	var errs []error
+	for _, e := range entries {
+		t, ok := parseTimestamp(e.DeletedAt)
+		if !ok || t.After(cutoff) {
+			continue
+		}
+		errs = append(errs, os.RemoveAll(filepath.Join(trashDir(base), e.Id)))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,419 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// A workspace is a folder holding projects, models, templates and trash.
+// Settings list them and record the active one. The default workspace is
+// the config folder itself, where Hyuga always kept its data.
+
+const defaultWorkspaceId = "default"
+
+type Workspace struct {
+	Id     string `json:"id"`
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Active bool   `json:"active"`
+}
+
+// appFiles belong to the app rather than to the workspace that happens to
+// share the config folder. Backups and moves leave them alone.
+var appFiles = []string{"settings.json"}
+
+func isAppFile(rel string) bool {
+	return slices.Contains(appFiles, filepath.ToSlash(rel))
+}
+
+func findWorkspace(s *Settings, id string) int {
+	return slices.IndexFunc(s.Workspaces, func(w Workspace) bool { return w.Id == id })
+}
+
+func (a *App) activeWorkspace() (*Workspace, error) {
+	s, err := currentSettings()
+	if err != nil {
+		return nil, err
+	}
+	i := findWorkspace(s, s.ActiveWorkspace)
+	if i == -1 {
+		return nil, fmt.Errorf("active workspace %s not found", s.ActiveWorkspace)
+	}
+	return &s.Workspaces[i], nil
+}
+
+// workspaceDir resolves the folder of the active workspace. App methods
+// use it instead of the config folder.
+func (a *App) workspaceDir() (string, error) {
+	w, err := a.activeWorkspace()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(w.Path, 0755); err != nil {
+		return "", fmt.Errorf("workspace %q is not available: %w", w.Name, err)
+	}
+	return w.Path, nil
+}
+
+func (a *App) ListWorkspaces() ([]Workspace, error) {
+	s, err := currentSettings()
+	if err != nil {
+		return nil, err
+	}
+	for i := range s.Workspaces {
+		s.Workspaces[i].Active = s.Workspaces[i].Id == s.ActiveWorkspace
+	}
+	return s.Workspaces, nil
+}
+
+// AddWorkspace registers path as a new workspace, asking for a folder when
+// path is empty. Existing Hyuga data in the folder is picked up as is.
+func (a *App) AddWorkspace(name string, path string) (*Workspace, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("workspace name is required")
+	}
+	var err error
+	if path == "" {
+		path, err = runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
+			Title:                "Carpeta del espacio de trabajo",
+			CanCreateDirectories: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if path == "" {
+			return nil, errors.New("no folder provided")
+		}
+	}
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, err
+	}
+	w := Workspace{Id: uuid.NewString(), Name: name, Path: path}
+	_, err = updateSettings(func(s *Settings) error {
+		if slices.ContainsFunc(s.Workspaces, func(w Workspace) bool { return w.Path == path }) {
+			return fmt.Errorf("%s is already a workspace", path)
+		}
+		s.Workspaces = append(s.Workspaces, w)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// RemoveWorkspace unregisters a workspace. Its folder is left untouched.
+func (a *App) RemoveWorkspace(id string) error {
+	_, err := updateSettings(func(s *Settings) error {
+		i := findWorkspace(s, id)
+		switch {
+		case i == -1:
+			return fmt.Errorf("workspace %s not found", id)
+		case id == s.ActiveWorkspace:
+			return errors.New("cannot remove the active workspace")
+		case id == defaultWorkspaceId:
+			return errors.New("cannot remove the default workspace")
+		}
+		s.Workspaces = slices.Delete(s.Workspaces, i, i+1)
+		return nil
+	})
+	return err
+}
+
+func (a *App) SwitchWorkspace(id string) error {
+	var path string
+	s, err := updateSettings(func(s *Settings) error {
+		i := findWorkspace(s, id)
+		if i == -1 {
+			return fmt.Errorf("workspace %s not found", id)
+		}
+		if err := os.MkdirAll(s.Workspaces[i].Path, 0755); err != nil {
+			return fmt.Errorf("workspace %q is not available: %w", s.Workspaces[i].Name, err)
+		}
+		s.ActiveWorkspace = id
+		path = s.Workspaces[i].Path
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	a.openWorkspace(path)
+	a.emitSettings(s)
+	return nil
+}
+
+// openWorkspace runs the housekeeping due whenever a workspace becomes
+// active: migrations, trash retention and the backup schedule.
+func (a *App) openWorkspace(base string) {
+	if err := migrateProjects(base); err != nil {
+		fmt.Println(concat("could not migrate projects: ", err.Error()))
+	}
+	if err := relinkModels(base); err != nil {
+		fmt.Println(concat("could not relink machotes: ", err.Error()))
+	}
+	s, err := currentSettings()
+	if err != nil {
+		fmt.Println(concat("could not read settings: ", err.Error()))
+		return
+	}
+	if err := purgeTrash(base, s.TrashRetentionDays); err != nil {
+		fmt.Println(concat("could not purge trash: ", err.Error()))
+	}
+	a.scheduleBackups(s.Backup)
+}
+
+// MoveWorkspace copies a workspace to dest, which must be missing or empty,
+// verifies every file against its source checksum, points the machotes
+// and the workspace at dest and only then deletes the old copy. Relinking
+// rewrites the documents holding machote paths after the checksums, so
+// those are the only files that differ from the source. A failed move
+// leaves dest as it found it.
+func (a *App) MoveWorkspace(id string, dest string) error {
+	var err error
+	if dest == "" {
+		dest, err = runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
+			Title:                "Nueva ubicación del espacio de trabajo",
+			CanCreateDirectories: true,
+		})
+		if err != nil {
+			return err
+		}
+		if dest == "" {
+			return errors.New("no folder provided")
+		}
+	}
+	dest, err = filepath.Abs(dest)
+	if err != nil {
+		return err
+	}
+	s, err := currentSettings()
+	if err != nil {
+		return err
+	}
+	i := findWorkspace(s, id)
+	if i == -1 {
+		return fmt.Errorf("workspace %s not found", id)
+	}
+	src := s.Workspaces[i].Path
+	if rel, err := filepath.Rel(src, dest); err == nil && !strings.HasPrefix(rel, "..") {
+		return errors.New("cannot move a workspace inside itself")
+	}
+	if entries, err := os.ReadDir(dest); err == nil && len(entries) > 0 {
+		return fmt.Errorf("%s is not empty", dest)
+	}
+
+	_, err = os.Stat(dest)
+	existed := err == nil
+	undo := func() {
+		if !existed {
+			os.RemoveAll(dest)
+			return
+		}
+		entries, _ := os.ReadDir(dest)
+		for _, e := range entries {
+			os.RemoveAll(filepath.Join(dest, e.Name()))
+		}
+	}
+	want, err := copyWorkspace(src, dest)
+	if err != nil {
+		undo()
+		return err
+	}
+	_, err = updateSettings(func(s *Settings) error {
+		if i := findWorkspace(s, id); i != -1 {
+			s.Workspaces[i].Path = dest
+		}
+		return nil
+	})
+	if err != nil {
+		undo()
+		return err
+	}
+	for rel := range want {
+		if err := os.Remove(filepath.Join(src, rel)); err != nil {
+			fmt.Println(concat("could not remove ", rel, ": ", err.Error()))
+		}
+	}
+	removeEmptyDirs(src)
+	if s.ActiveWorkspace == id {
+		a.openWorkspace(dest)
+	}
+	return nil
+}
+
+// copyWorkspace copies the workspace files of src to dest, checks them
+// against their source checksums and relinks the machotes of the copy. It
+// returns the checksums of src.
+func copyWorkspace(src, dest string) (map[string]string, error) {
+	want, err := hashTree(src)
+	if err != nil {
+		return nil, err
+	}
+	for rel := range want {
+		if err := os.MkdirAll(filepath.Join(dest, filepath.Dir(rel)), 0755); err != nil {
+			return nil, err
+		}
+		if err := copyFile(filepath.Join(src, rel), filepath.Join(dest, rel)); err != nil {
+			return nil, fmt.Errorf("failed to copy %s: %w", rel, err)
+		}
+	}
+	got, err := hashTree(dest)
+	if err != nil {
+		return nil, err
+	}
+	for rel, sum := range want {
+		if got[rel] != sum {
+			return nil, fmt.Errorf("copy of %s does not match the original, the workspace was not moved", rel)
+		}
+	}
+	if err := relinkModels(dest); err != nil {
+		return nil, fmt.Errorf("failed to relink machotes, the workspace was not moved: %w", err)
+	}
+	return want, nil
+}
+
+// hashTree returns the sha256 of every workspace file under dir, keyed by
+// its path relative to dir. App files are skipped.
+func hashTree(dir string) (map[string]string, error) {
+	res := map[string]string{}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil || isAppFile(rel) {
+			return err
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		h := sha256.New()
+		if _, err := io.Copy(h, f); err != nil {
+			return err
+		}
+		res[rel] = hex.EncodeToString(h.Sum(nil))
+		return nil
+	})
+	return res, err
+}
+
+// removeEmptyDirs deletes the empty folders left under dir, dir included.
+func removeEmptyDirs(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			removeEmptyDirs(filepath.Join(dir, e.Name()))
+		}
+	}
+	// fails, as intended, while anything is left
+	os.Remove(dir)
+}
+
+// Machotes are referenced by absolute path, so a workspace that moved,
+// or was restored somewhere else, still points at its old models folder.
+
+// relinkModel points ref at the copy of its image under the models folder
+// of base, if there is one. It reports whether ref changed.
+func relinkModel(base string, ref *string) bool {
+	if _, ok := hashFromSavedPath(*ref); !ok {
+		return false
+	}
+	p := filepath.Join(base, "models", "images", filepath.Base(*ref))
+	if p == *ref {
+		return false
+	}
+	if _, err := os.Stat(p); err != nil {
+		return false
+	}
+	*ref = p
+	return true
+}
+
+// relinkFile rewrites the machote references of the JSON document file
+// as v, using refs to list them. Missing files are skipped.
+func relinkFile[T any](base, file string, refs func(v *T) []*string) error {
+	var v T
+	if err := readJSON(file, &v); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("cannot read %s: %w", file, err)
+	}
+	changed := false
+	for _, ref := range refs(&v) {
+		if relinkModel(base, ref) {
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return writeJSON(file, v)
+}
+
+// relinkModels points every machote reference of the workspace at base:
+// the model library, projects, trashed projects and assets, templates
+// and publications.
+func relinkModels(base string) error {
+	projectRefs := func(p *Project) []*string {
+		refs := []*string{&p.DefaultModel}
+		for i := range p.Assets {
+			refs = append(refs, &p.Assets[i].Model)
+		}
+		return refs
+	}
+	errs := []error{
+		relinkFile(base, filepath.Join(base, "models", "models.json"), func(ms *[]Model) []*string {
+			var refs []*string
+			for i := range *ms {
+				refs = append(refs, &(*ms)[i].Value)
+			}
+			return refs
+		}),
+		relinkFile(base, templatesFile(base), func(ts *[]ProjectTemplate) []*string {
+			var refs []*string
+			for i := range *ts {
+				refs = append(refs, &(*ts)[i].DefaultModel)
+			}
+			return refs
+		}),
+		relinkFile(base, publicationsFile(base), func(ps *[]Publication) []*string {
+			var refs []*string
+			for i := range *ps {
+				refs = append(refs, &(*ps)[i].DefaultModel)
+			}
+			return refs
+		}),
+	}
+	projects, _ := filepath.Glob(filepath.Join(base, "projects", "project-*", "project.json"))
+	trashed, _ := filepath.Glob(filepath.Join(trashDir(base), "*", "project-*", "project.json"))
+	for _, file := range append(projects, trashed...) {
+		errs = append(errs, relinkFile(base, file, projectRefs))
+	}
+	assets, _ := filepath.Glob(filepath.Join(trashDir(base), "*", "asset.json"))
+	for _, file := range assets {
+		errs = append(errs, relinkFile(base, file, func(as *AssetMetadata) []*string { return []*string{&as.Model} }))
+	}
+	return errors.Join(errs...)
+}